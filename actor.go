@@ -0,0 +1,40 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// onBehalfOfHeader is already in canonical form.
+const onBehalfOfHeader = "X-On-Behalf-Of"
+
+type actorKey struct{}
+
+// OnBehalfOf attributes a mutating call to actor, e.g. the email of the
+// operator driving an internal admin tool, so audit logs record who made
+// the change and not only which API key. Reads ignore it.
+func OnBehalfOf(actor string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.actor = actor
+	}
+}
+
+// ContextWithActor attributes the mutating calls made with ctx to actor,
+// like OnBehalfOf, e.g. from middleware that knows who is signed in to the
+// admin tool.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func setActor(req *http.Request, cfg *callConfig) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return
+	}
+	actor := cfg.actor
+	if actor == "" {
+		actor, _ = req.Context().Value(actorKey{}).(string)
+	}
+	if actor != "" {
+		req.Header.Set(onBehalfOfHeader, actor)
+	}
+}
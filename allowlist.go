@@ -0,0 +1,154 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AllowedToken is an entry on the allowlist, used by projects in allowlist
+// mode where only registered tokens are accepted.
+type AllowedToken struct {
+	JwtID      string     `json:"jwt_id"`
+	AllowedAt  Timestamp  `json:"allowed_at"`
+	ExpiryDate *Timestamp `json:"expiry_date,omitempty"`
+	Metadata   Metadata   `json:"metadata,omitempty"`
+}
+
+// Expired reports whether the entry's expiry date has passed.
+func (t *AllowedToken) Expired() bool {
+	return t.ExpiryDate != nil && !t.ExpiryDate.IsZero() && !t.ExpiryDate.After(time.Now())
+}
+
+type AllowRequest struct {
+	JwtID string `json:"jwtId"`
+	// ExpiryDate is when the entry lapses, normally the token's exp claim;
+	// nil keeps it until Disallow.
+	ExpiryDate *time.Time `json:"expiryDate,omitempty"`
+	Metadata   Metadata   `json:"metadata,omitempty"`
+}
+
+// Validate checks r with the same rules as RevokeRequest.
+func (r AllowRequest) Validate() error {
+	if err := validateJwtID("jwtId", r.JwtID); err != nil {
+		return err
+	}
+	if err := r.Metadata.validate(); err != nil {
+		return err
+	}
+	if r.ExpiryDate != nil && !r.ExpiryDate.After(time.Now()) {
+		return &ValidationError{Field: "expiryDate", Message: "must be in the future"}
+	}
+	return nil
+}
+
+// AllowChecker is consulted by Middleware in allowlist mode; see
+// MiddlewareOptions.Allowlist.
+type AllowChecker interface {
+	IsAllowed(ctx context.Context, jwtID string) (bool, error)
+}
+
+// Allow registers a token on the allowlist, typically right after issuing it.
+func (s *RevocationsService) Allow(ctx context.Context, payload AllowRequest, opts ...CallOption) (*AllowedToken, error) {
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/allowlist", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[AllowedToken](ctx, s.client, req, "token", opts...)
+}
+
+// Disallow removes jwtID from the allowlist, rejecting it from then on.
+func (s *RevocationsService) Disallow(ctx context.Context, jwtID string, opts ...CallOption) error {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/allowlist/%s", s.client.baseURL, url.PathEscape(jwtID)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && !(newCallConfig(opts).dryRun && resp.StatusCode == http.StatusOK) {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *RevocationsService) GetAllowed(ctx context.Context, jwtID string, opts ...CallOption) (*AllowedToken, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/allowlist/%s", s.client.baseURL, url.PathEscape(jwtID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[AllowedToken](ctx, s.client, req, "token", opts...)
+}
+
+// IsAllowed asks the API whether jwtID is on the allowlist and its entry has
+// not expired.
+func (s *RevocationsService) IsAllowed(ctx context.Context, jwtID string, opts ...CallOption) (bool, error) {
+	token, err := s.GetAllowed(ctx, jwtID, opts...)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !token.Expired(), nil
+}
+
+// ListAllowed returns every entry on the allowlist.
+func (s *RevocationsService) ListAllowed(ctx context.Context, opts ...CallOption) ([]AllowedToken, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/allowlist", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]AllowedToken](ctx, s.client, req, "tokens", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+// AllowChecker returns an AllowChecker backed by IsAllowed, for
+// MiddlewareOptions.Allowlist.
+func (s *RevocationsService) AllowChecker(opts ...CallOption) AllowChecker {
+	return allowlistChecker{s: s, opts: opts}
+}
+
+type allowlistChecker struct {
+	s    *RevocationsService
+	opts []CallOption
+}
+
+func (a allowlistChecker) IsAllowed(ctx context.Context, jwtID string) (bool, error) {
+	return a.s.IsAllowed(ctx, jwtID, a.opts...)
+}
@@ -0,0 +1,56 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+	GranularityWeek Granularity = "week"
+)
+
+type AnalyticsQuery struct {
+	Granularity Granularity
+	From        time.Time
+	To          time.Time
+}
+
+type AnalyticsBucket struct {
+	Start       Timestamp `json:"start"`
+	End         Timestamp `json:"end"`
+	Revocations int       `json:"revocations"`
+	// ByReason breaks Revocations down by reason code.
+	ByReason map[ReasonCode]int `json:"by_reason,omitempty"`
+}
+
+func (s *RevocationsService) Analytics(ctx context.Context, query AnalyticsQuery, opts ...CallOption) ([]AnalyticsBucket, error) {
+	params := url.Values{}
+	if query.Granularity != "" {
+		params.Set("granularity", string(query.Granularity))
+	}
+	setTime(params, "from", query.From)
+	setTime(params, "to", query.To)
+
+	endpoint := fmt.Sprintf("%s/api/revocations/analytics", s.client.baseURL)
+	if encoded := params.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]AnalyticsBucket](ctx, s.client, req, "data", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
@@ -0,0 +1,39 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RevocationAPI is the set of revocation operations offered by the
+// Revocations sub-client. Code that depends on RevocationAPI rather than
+// *RevocationsService can swap in a fake, a cache-backed wrapper, or a
+// generated mock. The other sub-clients are not part of it.
+type RevocationAPI interface {
+	Revoke(ctx context.Context, payload RevokeRequest, opts ...CallOption) (*RevokedToken, error)
+	RevokeBatch(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) ([]RevokedToken, error)
+	RevokeBySubject(ctx context.Context, sub string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error)
+	RevokeByIssuer(ctx context.Context, issuer string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error)
+	RevokeByAudience(ctx context.Context, audience string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error)
+	RevokeAll(ctx context.Context, params RevokeAllOptions, opts ...CallOption) (*ScopedRevocationResult, error)
+
+	Get(ctx context.Context, jwtID string, opts ...CallOption) (*RevokedToken, error)
+	IsRevoked(ctx context.Context, jwtID string, opts ...CallOption) (bool, error)
+	Update(ctx context.Context, jwtID string, update UpdateRequest, opts ...CallOption) (*RevokedToken, error)
+	Delete(ctx context.Context, jwtID string, opts ...CallOption) error
+	DeleteMany(ctx context.Context, jwtIDs []string, opts ...CallOption) error
+
+	List(ctx context.Context, params ListOptions, opts ...CallOption) ([]RevokedToken, error)
+	ListPage(ctx context.Context, params ListOptions, opts ...CallOption) (*RevocationPage, error)
+	Stream(ctx context.Context, params ListOptions, fn func(RevokedToken) error, opts ...CallOption) error
+	Changes(ctx context.Context, since time.Time, opts ...CallOption) (*ChangeSet, error)
+
+	Export(ctx context.Context, w io.Writer, format ExportFormat, opts ...CallOption) error
+	Import(ctx context.Context, r io.Reader, opts ImportOptions, callOpts ...CallOption) (*ImportReport, error)
+
+	Stats(ctx context.Context, opts ...CallOption) (*RevocationStats, error)
+	Analytics(ctx context.Context, query AnalyticsQuery, opts ...CallOption) ([]AnalyticsBucket, error)
+}
+
+var _ RevocationAPI = (*RevocationsService)(nil)
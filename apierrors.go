@@ -0,0 +1,80 @@
+package jwtrevokeapi
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the machine-readable code in the API's error responses.
+type ErrorCode string
+
+const (
+	CodeInvalidRequest   ErrorCode = "invalid_request"
+	CodeValidationFailed ErrorCode = "validation_failed"
+	CodeUnauthorized     ErrorCode = "unauthorized"
+	CodeForbidden        ErrorCode = "forbidden"
+	CodeNotFound         ErrorCode = "not_found"
+	CodeConflict         ErrorCode = "conflict"
+	CodeRateLimited      ErrorCode = "rate_limited"
+	CodeQuotaExceeded    ErrorCode = "quota_exceeded"
+	CodeInternal         ErrorCode = "internal_error"
+)
+
+// FieldError is a problem with one field of a rejected request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// errorEnvelope is the body of the API's error responses.
+type errorEnvelope struct {
+	Message          string       `json:"message"`
+	Code             ErrorCode    `json:"code"`
+	Errors           []FieldError `json:"errors"`
+	DocumentationURL string       `json:"documentation_url"`
+	Data             interface{}  `json:"data"`
+}
+
+// ErrorCodeOf returns the API error code carried by err, or "" when err is
+// not a *ClientError or the response had none.
+func ErrorCodeOf(err error) ErrorCode {
+	var clientErr *ClientError
+	if errors.As(err, &clientErr) {
+		return clientErr.Code
+	}
+	return ""
+}
+
+// IsValidationError reports whether err is a request the SDK or the API
+// rejected as invalid: a *ValidationError, or a *ClientError with
+// CodeValidationFailed or field errors.
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return true
+	}
+	var clientErr *ClientError
+	return errors.As(err, &clientErr) && (clientErr.Code == CodeValidationFailed || len(clientErr.FieldErrors) > 0)
+}
+
+// IsRateLimited reports whether err is a 429 the SDK gave up retrying.
+func IsRateLimited(err error) bool {
+	var clientErr *ClientError
+	return errors.As(err, &clientErr) && (clientErr.StatusCode == http.StatusTooManyRequests || clientErr.Code == CodeRateLimited)
+}
+
+// IsAuthError reports whether err is the API rejecting the credentials or
+// their permissions.
+func IsAuthError(err error) bool {
+	var clientErr *ClientError
+	if !errors.As(err, &clientErr) {
+		return false
+	}
+	switch {
+	case clientErr.StatusCode == http.StatusUnauthorized, clientErr.StatusCode == http.StatusForbidden:
+		return true
+	default:
+		return clientErr.Code == CodeUnauthorized || clientErr.Code == CodeForbidden
+	}
+}
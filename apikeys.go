@@ -0,0 +1,108 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type APIKeysService struct {
+	client *Client
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes,omitempty"`
+	CreatedAt  Timestamp  `json:"created_at"`
+	LastUsedAt *Timestamp `json:"last_used_at,omitempty"`
+	ExpiresAt  *Timestamp `json:"expires_at,omitempty"`
+}
+
+// CreatedAPIKey is returned by Create and Rotate; Secret is only ever shown once.
+type CreatedAPIKey struct {
+	APIKey
+	Secret string `json:"secret"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+type RotateAPIKeyRequest struct {
+	// GracePeriod keeps the old secret valid for this long after rotation.
+	GracePeriod time.Duration `json:"-"`
+}
+
+func (s *APIKeysService) Create(ctx context.Context, create CreateAPIKeyRequest, opts ...CallOption) (*CreatedAPIKey, error) {
+	body, err := json.Marshal(create)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/keys", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[CreatedAPIKey](ctx, s.client, req, "key", opts...)
+}
+
+func (s *APIKeysService) List(ctx context.Context, opts ...CallOption) ([]APIKey, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/keys", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]APIKey](ctx, s.client, req, "data", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (s *APIKeysService) Revoke(ctx context.Context, keyID string, opts ...CallOption) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/keys/%s", s.client.baseURL, url.PathEscape(keyID)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *APIKeysService) Rotate(ctx context.Context, keyID string, rotate RotateAPIKeyRequest, opts ...CallOption) (*CreatedAPIKey, error) {
+	body, err := json.Marshal(struct {
+		GracePeriodSeconds int64 `json:"gracePeriodSeconds,omitempty"`
+	}{int64(rotate.GracePeriod / time.Second)})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/keys/%s/rotate", s.client.baseURL, url.PathEscape(keyID)), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[CreatedAPIKey](ctx, s.client, req, "key", opts...)
+}
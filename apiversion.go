@@ -0,0 +1,48 @@
+package jwtrevokeapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type APIVersion string
+
+const (
+	APIVersionV1 APIVersion = "v1"
+	APIVersionV2 APIVersion = "v2"
+)
+
+// WithAPIVersion selects the API version requests are sent to. v1, the
+// default, uses the unversioned /api/ paths; later versions are served under
+// /api/<version>/.
+func WithAPIVersion(version APIVersion) ClientOption {
+	return func(c *Client) {
+		c.apiVersion = version
+	}
+}
+
+// ForAPIVersion sends a single call to version, overriding WithAPIVersion, so
+// endpoints can be moved to a new version one at a time.
+func ForAPIVersion(version APIVersion) CallOption {
+	return func(cfg *callConfig) {
+		cfg.apiVersion = version
+	}
+}
+
+func (c *Client) applyAPIVersion(req *http.Request, version APIVersion) {
+	if version == "" || version == APIVersionV1 {
+		return
+	}
+	prefix := c.baseURL + "/api/"
+	raw := req.URL.String()
+	if !strings.HasPrefix(raw, prefix) {
+		return
+	}
+	u, err := url.Parse(prefix + string(version) + "/" + strings.TrimPrefix(raw, prefix))
+	if err != nil {
+		return
+	}
+	req.URL = u
+	req.Header.Set("X-API-Version", string(version))
+}
@@ -0,0 +1,104 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Audit actions of administrative changes to the account, besides the
+// revocation.* actions of revocations.
+const (
+	AuditAPIKeyCreated     = "api_key.created"
+	AuditAPIKeyRotated     = "api_key.rotated"
+	AuditAPIKeyRevoked     = "api_key.revoked"
+	AuditWebhookCreated    = "webhook.created"
+	AuditWebhookUpdated    = "webhook.updated"
+	AuditWebhookDeleted    = "webhook.deleted"
+	AuditMemberAdded       = "member.added"
+	AuditMemberRoleChanged = "member.role_changed"
+	AuditMemberRemoved     = "member.removed"
+)
+
+type AuditChangeSet struct {
+	Entries []AuditLogEntry `json:"data"`
+	// ServerTime is the point up to which Entries is complete; pass it as
+	// since on the next call.
+	ServerTime Timestamp `json:"server_time"`
+}
+
+// Changes returns audit entries recorded after since, oldest first.
+func (s *AuditLogsService) Changes(ctx context.Context, since time.Time, opts ...CallOption) (*AuditChangeSet, error) {
+	return s.changes(ctx, since, 0, opts...)
+}
+
+// changes asks the server to hold the request for up to wait when there are
+// no entries yet.
+func (s *AuditLogsService) changes(ctx context.Context, since time.Time, wait time.Duration, opts ...CallOption) (*AuditChangeSet, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339Nano))
+	if wait > 0 {
+		params.Set("wait", strconv.Itoa(int(wait/time.Second)))
+		opts = append(opts, withLongPoll(wait))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/audit-logs/changes?%s", s.client.baseURL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[AuditChangeSet](ctx, s.client, req, "", opts...)
+}
+
+// AuditSubscription delivers audit entries as they are recorded, long
+// polling like Subscription.
+type AuditSubscription struct {
+	entries chan AuditLogEntry
+	done    chan struct{}
+	err     error
+}
+
+// Subscribe delivers every audit entry after since, in order, covering
+// configuration changes such as API keys created, webhooks changed and
+// members added as well as revocations, e.g. to feed a SIEM. It ends and
+// retries like RevocationsService.Subscribe.
+func (s *AuditLogsService) Subscribe(ctx context.Context, since time.Time, opts ...CallOption) *AuditSubscription {
+	sub := &AuditSubscription{entries: make(chan AuditLogEntry), done: make(chan struct{})}
+	s.client.startPolling(ctx, sub.done, func(ctx context.Context) {
+		defer close(sub.entries)
+		sub.err = pollChanges(ctx, s.client, since, opts, sub.entries,
+			func(ctx context.Context, since time.Time, wait time.Duration, opts []CallOption) ([]AuditLogEntry, Timestamp, error) {
+				set, err := s.changes(ctx, since, wait, opts...)
+				if err != nil {
+					return nil, Timestamp{}, err
+				}
+				return set.Entries, set.ServerTime, nil
+			})
+	})
+	return sub
+}
+
+// Entries is closed when the subscription ends; Err then reports why.
+func (sub *AuditSubscription) Entries() <-chan AuditLogEntry {
+	return sub.entries
+}
+
+// Err reports why the subscription ended, like Subscription.Err. Only call
+// it after Entries has been closed.
+func (sub *AuditSubscription) Err() error {
+	return sub.err
+}
+
+// Active reports whether the subscription is still running, and otherwise
+// the error that ended it.
+func (sub *AuditSubscription) Active() (bool, error) {
+	select {
+	case <-sub.done:
+		return false, sub.err
+	default:
+		return true, nil
+	}
+}
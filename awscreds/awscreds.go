@@ -0,0 +1,107 @@
+// Package awscreds loads the JWT Revoke API key from AWS Secrets Manager, so
+// the key never has to live in environment variables or images.
+package awscreds
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+const defaultRefreshInterval = 5 * time.Minute
+
+var _ jwtrevokeapi.CredentialsProvider = (*Provider)(nil)
+
+// SecretsManagerAPI is the part of *secretsmanager.Client the provider uses.
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// Provider re-fetches the secret once the refresh interval has passed, or
+// straight away after the API rejects the current key.
+type Provider struct {
+	client       SecretsManagerAPI
+	secretID     string
+	field        string
+	versionStage string
+	refresh      time.Duration
+	cache        *jwtrevokeapi.CachingCredentials
+}
+
+type Option func(*Provider)
+
+// WithField treats the secret string as a JSON object and reads the API key
+// from field. By default the whole secret string is the key.
+func WithField(field string) Option {
+	return func(p *Provider) {
+		p.field = field
+	}
+}
+
+// WithVersionStage reads a staging label other than AWSCURRENT, e.g.
+// AWSPENDING while testing a rotation.
+func WithVersionStage(stage string) Option {
+	return func(p *Provider) {
+		p.versionStage = stage
+	}
+}
+
+// WithRefreshInterval sets how long a fetched key is used before Secrets
+// Manager is asked again. Defaults to five minutes.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		p.refresh = d
+	}
+}
+
+// New reads the API key from the secret with the given name or ARN.
+func New(client SecretsManagerAPI, secretID string, options ...Option) *Provider {
+	p := &Provider{client: client, secretID: secretID, refresh: defaultRefreshInterval}
+	for _, option := range options {
+		option(p)
+	}
+	p.cache = jwtrevokeapi.NewCachingCredentials(jwtrevokeapi.CredentialsFunc(p.fetch), p.refresh)
+	return p
+}
+
+func (p *Provider) APIKey(ctx context.Context) (string, error) {
+	return p.cache.APIKey(ctx)
+}
+
+// Invalidate makes the next APIKey call fetch the secret again.
+func (p *Provider) Invalidate() {
+	p.cache.Invalidate()
+}
+
+func (p *Provider) fetch(ctx context.Context) (string, error) {
+	input := &secretsmanager.GetSecretValueInput{SecretId: aws.String(p.secretID)}
+	if p.versionStage != "" {
+		input.VersionStage = aws.String(p.versionStage)
+	}
+	out, err := p.client.GetSecretValue(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("awscreds: reading %s: %w", p.secretID, err)
+	}
+	secret := aws.ToString(out.SecretString)
+	if p.field == "" {
+		if secret == "" {
+			return "", fmt.Errorf("awscreds: %s has no secret string", p.secretID)
+		}
+		return secret, nil
+	}
+
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("awscreds: %s is not a JSON object: %w", p.secretID, err)
+	}
+	key := fields[p.field]
+	if key == "" {
+		return "", fmt.Errorf("awscreds: %s has no %q field", p.secretID, p.field)
+	}
+	return key, nil
+}
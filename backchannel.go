@@ -0,0 +1,179 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const backchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// defaultLogoutTokenMaxAge is how old a logout token's iat may be.
+const defaultLogoutTokenMaxAge = 5 * time.Minute
+
+type BackchannelLogoutOptions struct {
+	// Issuer is the OpenID provider's issuer identifier. Required.
+	Issuer string
+	// ClientID is this relying party's client ID, which the token's aud
+	// must contain. Required.
+	ClientID string
+	// KeySet verifies token signatures, typically NewRemoteKeySet with the
+	// provider's jwks_uri. Required.
+	KeySet KeySet
+	// MaxAge rejects tokens issued longer ago than this. Defaults to five
+	// minutes.
+	MaxAge time.Duration
+	// Reason is recorded on the revocations. Defaults to ReasonLogout.
+	Reason ReasonCode
+}
+
+// logoutToken holds the claims of a validated logout token.
+type logoutToken struct {
+	Issuer    string
+	Subject   string
+	SessionID string
+	JwtID     string
+	IssuedAt  time.Time
+}
+
+// BackchannelLogoutHandler serves an OpenID Connect Back-Channel Logout
+// endpoint. Each logout token is validated as the specification requires
+// and turned into revocations: by session when it carries a sid, otherwise
+// every token of its sub. Replayed tokens are rejected.
+func BackchannelLogoutHandler(client *Client, opts BackchannelLogoutOptions) http.Handler {
+	if opts.MaxAge == 0 {
+		opts.MaxAge = defaultLogoutTokenMaxAge
+	}
+	if opts.Reason == "" {
+		opts.Reason = ReasonLogout
+	}
+	seen := &replayCache{seen: map[string]time.Time{}}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeLogoutError(w, http.StatusMethodNotAllowed, "invalid_request", "logout requests must be POSTed")
+			return
+		}
+
+		token, err := opts.verify(r.Context(), r.PostFormValue("logout_token"))
+		if err != nil {
+			writeLogoutError(w, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		if !seen.add(token.Issuer+" "+token.JwtID, token.IssuedAt.Add(opts.MaxAge)) {
+			writeLogoutError(w, http.StatusBadRequest, "invalid_request", "logout token has already been used")
+			return
+		}
+
+		if token.SessionID != "" {
+			_, err = client.Revocations.RevokeSession(r.Context(), token.SessionID, opts.Reason)
+		} else {
+			_, err = client.Revocations.RevokeBySubject(r.Context(), token.Subject, opts.Reason)
+		}
+		if err != nil {
+			seen.remove(token.Issuer + " " + token.JwtID)
+			client.log(r.Context(), slog.LevelWarn, "jwtrevoke: back-channel logout failed",
+				"sub", token.Subject, "sid", token.SessionID, "error", err)
+			writeLogoutError(w, http.StatusServiceUnavailable, "temporarily_unavailable", "revocation failed")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// verify applies the validation rules of OpenID Connect Back-Channel Logout
+// 1.0, section 2.6.
+func (opts BackchannelLogoutOptions) verify(ctx context.Context, raw string) (*logoutToken, error) {
+	if raw == "" {
+		return nil, errors.New("missing logout_token")
+	}
+	payload, err := opts.KeySet.VerifySignature(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims struct {
+		Issuer    string                     `json:"iss"`
+		Subject   string                     `json:"sub"`
+		SessionID string                     `json:"sid"`
+		Audience  audience                   `json:"aud"`
+		IssuedAt  *Timestamp                 `json:"iat"`
+		ExpiresAt *Timestamp                 `json:"exp"`
+		JwtID     string                     `json:"jti"`
+		Events    map[string]json.RawMessage `json:"events"`
+		Nonce     *json.RawMessage           `json:"nonce"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.New("logout token claims are malformed")
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != opts.Issuer:
+		return nil, errors.New("logout token has the wrong issuer")
+	case !slices.Contains(claims.Audience, opts.ClientID):
+		return nil, errors.New("logout token is not intended for this client")
+	case claims.IssuedAt == nil || claims.IssuedAt.Before(now.Add(-opts.MaxAge)) || claims.IssuedAt.After(now.Add(time.Minute)):
+		return nil, errors.New("logout token iat is missing or out of range")
+	case claims.ExpiresAt != nil && !claims.ExpiresAt.After(now):
+		return nil, errors.New("logout token has expired")
+	case claims.JwtID == "":
+		return nil, errors.New("logout token has no jti")
+	case claims.Events[backchannelLogoutEvent] == nil:
+		return nil, errors.New("logout token has no back-channel logout event")
+	case claims.Subject == "" && claims.SessionID == "":
+		return nil, errors.New("logout token has neither sub nor sid")
+	case claims.Nonce != nil:
+		return nil, errors.New("logout token must not contain a nonce")
+	}
+
+	return &logoutToken{
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		SessionID: claims.SessionID,
+		JwtID:     claims.JwtID,
+		IssuedAt:  claims.IssuedAt.Time,
+	}, nil
+}
+
+// replayCache remembers logout token IDs until they would be too old to
+// be accepted anyway.
+type replayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (c *replayCache) add(id string, until time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, exp := range c.seen {
+		if now.After(exp) {
+			delete(c.seen, k)
+		}
+	}
+	if _, ok := c.seen[id]; ok {
+		return false
+	}
+	c.seen[id] = until
+	return true
+}
+
+func (c *replayCache) remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, id)
+}
+
+func writeLogoutError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code, "error_description": description})
+}
@@ -0,0 +1,232 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type batchRevokeRequest struct {
+	Revocations []RevokeRequest `json:"revocations"`
+}
+
+// maxRevokeBatchSize is the largest batch sent in one call, unless the
+// account's Capabilities say otherwise.
+const maxRevokeBatchSize = 100
+
+// RevokeBatch revokes several tokens. Up to the account's
+// Capabilities.MaxBatchSize revocations, 100 by default, are sent in a
+// single call; larger slices are split into chunks sent concurrently,
+// bounded by WithConcurrency. Tokens are returned in request order. If a
+// chunk fails, the chunks that have not started are cancelled and the error
+// is returned, but chunks that already succeeded stay revoked. Every
+// revocation is validated before anything is sent.
+func (s *RevocationsService) RevokeBatch(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) ([]RevokedToken, error) {
+	return s.RevokeBatchWithOptions(ctx, revocations, BatchOptions{}, opts...)
+}
+
+type BatchOptions struct {
+	// Atomic revokes every entry or none, for jobs that must not leave the
+	// list half-applied. The API validates the whole batch first and, if it
+	// rejects any entry, revokes nothing and reports every problem in a
+	// *BatchValidationError. Atomic batches are sent in a single call, so
+	// they hold at most the account's Capabilities.MaxBatchSize entries,
+	// 100 by default.
+	Atomic bool
+}
+
+// BatchValidationError is returned when the API rejects an atomic batch.
+// Nothing was revoked.
+type BatchValidationError struct {
+	// Items lists the rejected entries, with Code and Err set.
+	Items []BatchItem
+	Err   *ClientError
+}
+
+func (e *BatchValidationError) Error() string {
+	return fmt.Sprintf("jwt-revoke: atomic batch rejected with %d invalid entries: %v", len(e.Items), e.Err)
+}
+
+func (e *BatchValidationError) Unwrap() error {
+	return e.Err
+}
+
+// RevokeBatchWithOptions is RevokeBatch with an atomic mode.
+func (s *RevocationsService) RevokeBatchWithOptions(ctx context.Context, revocations []RevokeRequest, options BatchOptions, opts ...CallOption) ([]RevokedToken, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	revocations, err := s.client.resolveBatch(revocations)
+	if err != nil {
+		return nil, err
+	}
+
+	size := s.client.batchSize(ctx, opts...)
+	if options.Atomic {
+		if len(revocations) > size {
+			return nil, &ValidationError{Field: "revocations", Message: fmt.Sprintf("must have at most %d entries in an atomic batch", size)}
+		}
+		result, err := s.client.sendBatch(ctx, revocations, batchAtomic, opts...)
+		if err != nil {
+			return nil, atomicBatchError(revocations, err)
+		}
+		return result.Tokens, nil
+	}
+	if len(revocations) <= size {
+		return s.client.revokeBatch(ctx, revocations, opts...)
+	}
+
+	chunks := make([][]RevokedToken, (len(revocations)+size-1)/size)
+	g, gctx := newGroup(ctx, s.client.concurrency)
+	for i := range chunks {
+		chunk := revocations[i*size : min((i+1)*size, len(revocations))]
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			tokens, err := s.client.revokeBatch(gctx, chunk, opts...)
+			chunks[i] = tokens
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	tokens := make([]RevokedToken, 0, len(revocations))
+	for _, chunk := range chunks {
+		tokens = append(tokens, chunk...)
+	}
+	return tokens, nil
+}
+
+// resolveBatch fills in the revocations' claims from their tokens and
+// validates them.
+func (c *Client) resolveBatch(revocations []RevokeRequest) ([]RevokeRequest, error) {
+	now := c.clock.Now()
+	resolved := make([]RevokeRequest, len(revocations))
+	for i, r := range revocations {
+		r, err := r.withTokenClaims(c.expirySkew)
+		if err == nil {
+			err = r.validate(now)
+		}
+		if err != nil {
+			err.Field = fmt.Sprintf("revocations[%d].%s", i, err.Field)
+			return nil, err
+		}
+		resolved[i] = r
+	}
+	return resolved, nil
+}
+
+func (c *Client) revokeBatch(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) ([]RevokedToken, error) {
+	result, err := c.sendBatch(ctx, revocations, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tokens, nil
+}
+
+type batchRevokeResponse struct {
+	// Tokens are the revocations that succeeded, in request order.
+	Tokens []RevokedToken `json:"tokens"`
+	// Errors are only reported for partial batches; otherwise any invalid
+	// item fails the whole call.
+	Errors []batchItemError `json:"errors,omitempty"`
+}
+
+type batchItemError struct {
+	// Index is the item's position in the call.
+	Index   int       `json:"index"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// Batch modes: a partial batch asks the API to revoke the items it can and
+// report the others in Errors, an atomic one to revoke all or nothing.
+const (
+	batchPartial = "partial"
+	batchAtomic  = "atomic"
+)
+
+// sendBatch sends one batch call in the given mode, or the default one in
+// which an invalid item fails the call but the API does not roll back.
+func (c *Client) sendBatch(ctx context.Context, revocations []RevokeRequest, mode string, opts ...CallOption) (*batchRevokeResponse, error) {
+	body, contentType, err := c.encodeBulk(batchRevokeRequest{Revocations: revocations})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/api/revocations/batch", c.baseURL)
+	if mode != "" {
+		endpoint += "?" + mode + "=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	c.acceptBulk(req)
+	if err := c.compressRequest(req, body); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if mode != batchPartial {
+		for _, r := range revocations {
+			c.prefilterRevoked(r.JwtID)
+		}
+	}
+
+	var result batchRevokeResponse
+	if err := c.decodeBulk(resp.Body, resp.Header.Get("Content-Type"), &result); err != nil {
+		return nil, err
+	}
+
+	if mode == batchPartial {
+		failed := make(map[int]bool, len(result.Errors))
+		for _, e := range result.Errors {
+			failed[e.Index] = true
+		}
+		for i, r := range revocations {
+			if !failed[i] {
+				c.prefilterRevoked(r.JwtID)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// atomicBatchError turns the API's rejection of an atomic batch, whose field
+// errors name entries as revocations[i], into a *BatchValidationError.
+func atomicBatchError(revocations []RevokeRequest, err error) error {
+	var clientErr *ClientError
+	if !errors.As(err, &clientErr) || len(clientErr.FieldErrors) == 0 {
+		return err
+	}
+	batchErr := &BatchValidationError{Err: clientErr}
+	for _, fe := range clientErr.FieldErrors {
+		var i int
+		if _, scanErr := fmt.Sscanf(fe.Field, "revocations[%d]", &i); scanErr != nil || i < 0 || i >= len(revocations) {
+			continue
+		}
+		code := ErrorCode(fe.Code)
+		if code == "" {
+			code = clientErr.Code
+		}
+		batchErr.Items = append(batchErr.Items, BatchItem{
+			Index:  i,
+			JwtID:  revocations[i].JwtID,
+			Status: BatchItemFailed,
+			Code:   code,
+			Err:    fmt.Errorf("jwt-revoke: %s", fe.Message),
+		})
+	}
+	return batchErr
+}
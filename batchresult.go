@@ -0,0 +1,238 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type BatchItemStatus string
+
+const (
+	BatchItemSucceeded BatchItemStatus = "succeeded"
+	BatchItemFailed    BatchItemStatus = "failed"
+	// BatchItemSkipped items were not attempted because ctx ended first.
+	BatchItemSkipped BatchItemStatus = "skipped"
+)
+
+// BatchItem is the outcome of one item of a batch operation.
+type BatchItem struct {
+	// Index is the item's position in the request.
+	Index  int
+	JwtID  string
+	Status BatchItemStatus
+	// Code classifies a failure; see the CodeXxx constants. Items the SDK
+	// rejected before sending have CodeValidationFailed, and transport
+	// errors have none.
+	Code ErrorCode
+	Err  error
+	// Token is the revocation made, for revocations that succeeded.
+	Token *RevokedToken
+}
+
+// BatchResult reports every item of a batch operation, in request order.
+type BatchResult struct {
+	Items []BatchItem
+
+	// retry attempts the items at indexes again.
+	retry func(ctx context.Context, indexes []int) []BatchItem
+}
+
+func (r *BatchResult) Succeeded() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if item.Status == BatchItemSucceeded {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Failed returns the items that failed or were skipped.
+func (r *BatchResult) Failed() []BatchItem {
+	var items []BatchItem
+	for _, item := range r.Items {
+		if item.Status != BatchItemSucceeded {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Err joins the errors of the failed items, or returns nil when every item
+// succeeded.
+func (r *BatchResult) Err() error {
+	var errs []error
+	for _, item := range r.Failed() {
+		errs = append(errs, fmt.Errorf("items[%d] (%s): %w", item.Index, item.JwtID, item.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// RetryFailed attempts the failed and skipped items again, except those
+// rejected as invalid, which would only fail again, and returns the merged
+// result. Like the call that produced r, it only returns an error when ctx
+// ends before every item was attempted.
+func (r *BatchResult) RetryFailed(ctx context.Context) (*BatchResult, error) {
+	merged := &BatchResult{Items: append([]BatchItem(nil), r.Items...), retry: r.retry}
+	var indexes []int
+	for _, item := range r.Items {
+		if item.Status != BatchItemSucceeded && item.Code != CodeValidationFailed && item.Code != CodeInvalidRequest {
+			indexes = append(indexes, item.Index)
+		}
+	}
+	if len(indexes) == 0 || r.retry == nil {
+		return merged, nil
+	}
+	for _, item := range r.retry(ctx, indexes) {
+		merged.Items[item.Index] = item
+	}
+	return merged.finish(ctx)
+}
+
+// finish reports why items were skipped.
+func (r *BatchResult) finish(ctx context.Context) (*BatchResult, error) {
+	if ctx.Err() == nil {
+		return r, nil
+	}
+	skipped := false
+	for i := range r.Items {
+		if r.Items[i].Status == BatchItemSkipped {
+			r.Items[i].Err = context.Cause(ctx)
+			skipped = true
+		}
+	}
+	if skipped {
+		return r, context.Cause(ctx)
+	}
+	return r, nil
+}
+
+// RevokeBatchPartial is RevokeBatch for batches that may partly fail.
+// Rather than stopping at the first problem, it revokes every item it can
+// and reports each one in the result: invalid items are not sent, and the
+// API rejects items one by one instead of failing their whole chunk. The
+// error is only set when ctx ends before every item was attempted; the
+// result then lists the rest as skipped.
+func (s *RevocationsService) RevokeBatchPartial(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) (*BatchResult, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	now := s.client.clock.Now()
+	resolved := make([]RevokeRequest, len(revocations))
+	result := &BatchResult{Items: make([]BatchItem, len(revocations))}
+	var valid []int
+	for i, r := range revocations {
+		result.Items[i] = BatchItem{Index: i, JwtID: r.JwtID, Status: BatchItemSkipped}
+		r, err := r.withTokenClaims(s.client.expirySkew)
+		if err == nil {
+			err = r.validate(now)
+		}
+		if err != nil {
+			err.Field = fmt.Sprintf("revocations[%d].%s", i, err.Field)
+			result.Items[i].Status, result.Items[i].Code, result.Items[i].Err = BatchItemFailed, CodeValidationFailed, err
+			continue
+		}
+		resolved[i] = r
+		result.Items[i].JwtID = r.JwtID
+		valid = append(valid, i)
+	}
+
+	result.retry = func(ctx context.Context, indexes []int) []BatchItem {
+		return s.client.revokeItems(ContextWithBulkPriority(ctx), resolved, indexes, opts)
+	}
+	for _, item := range result.retry(ctx, valid) {
+		result.Items[item.Index] = item
+	}
+	return result.finish(ctx)
+}
+
+// revokeItems sends the revocations at indexes as partial batches.
+func (c *Client) revokeItems(ctx context.Context, revocations []RevokeRequest, indexes []int, opts []CallOption) []BatchItem {
+	items := make([]BatchItem, len(indexes))
+	for i, idx := range indexes {
+		items[i] = BatchItem{Index: idx, JwtID: revocations[idx].JwtID, Status: BatchItemSkipped}
+	}
+
+	size := c.batchSize(ctx, opts...)
+	g, gctx := newGroup(ctx, c.concurrency)
+	for start := 0; start < len(items); start += size {
+		chunk := items[start:min(start+size, len(items))]
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				return nil
+			}
+			reqs := make([]RevokeRequest, len(chunk))
+			for j, item := range chunk {
+				reqs[j] = revocations[item.Index]
+			}
+			resp, err := c.sendBatch(gctx, reqs, batchPartial, opts...)
+			if err != nil {
+				for j := range chunk {
+					chunk[j].Status, chunk[j].Code, chunk[j].Err = BatchItemFailed, ErrorCodeOf(err), err
+				}
+				return nil
+			}
+			failed := make(map[int]batchItemError, len(resp.Errors))
+			for _, e := range resp.Errors {
+				failed[e.Index] = e
+			}
+			tokens := resp.Tokens
+			for j := range chunk {
+				if e, ok := failed[j]; ok {
+					chunk[j].Status, chunk[j].Code, chunk[j].Err = BatchItemFailed, e.Code, fmt.Errorf("jwt-revoke: %s", e.Message)
+					continue
+				}
+				chunk[j].Status = BatchItemSucceeded
+				if len(tokens) > 0 {
+					chunk[j].Token = &tokens[0]
+					tokens = tokens[1:]
+				}
+			}
+			return nil
+		})
+	}
+	g.Wait()
+	return items
+}
+
+// DeleteManyPartial is DeleteMany for deletions that may partly fail. Every
+// deletion is attempted, bounded by WithConcurrency, and reported in the
+// result, e.g. as CodeNotFound for tokens that were not revoked. The error
+// is only set when ctx ends before every deletion was attempted.
+func (s *RevocationsService) DeleteManyPartial(ctx context.Context, jwtIDs []string, opts ...CallOption) (*BatchResult, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	result := &BatchResult{Items: make([]BatchItem, len(jwtIDs))}
+	var valid []int
+	for i, jwtID := range jwtIDs {
+		result.Items[i] = BatchItem{Index: i, JwtID: jwtID, Status: BatchItemSkipped}
+		if err := validateJwtID(fmt.Sprintf("jwtIDs[%d]", i), jwtID); err != nil {
+			result.Items[i].Status, result.Items[i].Code, result.Items[i].Err = BatchItemFailed, CodeValidationFailed, err
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	result.retry = func(ctx context.Context, indexes []int) []BatchItem {
+		items := make([]BatchItem, len(indexes))
+		g, gctx := newGroup(ContextWithBulkPriority(ctx), s.client.concurrency)
+		for i, idx := range indexes {
+			items[i] = BatchItem{Index: idx, JwtID: jwtIDs[idx], Status: BatchItemSkipped}
+			g.Go(func() error {
+				if gctx.Err() != nil {
+					return nil
+				}
+				if err := s.Delete(gctx, jwtIDs[idx], opts...); err != nil {
+					items[i].Status, items[i].Code, items[i].Err = BatchItemFailed, ErrorCodeOf(err), err
+					return nil
+				}
+				items[i].Status = BatchItemSucceeded
+				return nil
+			})
+		}
+		g.Wait()
+		return items
+	}
+	for _, item := range result.retry(ctx, valid) {
+		result.Items[item.Index] = item
+	}
+	return result.finish(ctx)
+}
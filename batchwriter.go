@@ -0,0 +1,240 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var ErrBatchWriterClosed = errors.New("jwt-revoke: batch writer is closed")
+
+const (
+	defaultBatchWriterSize          = 100
+	defaultBatchWriterFlushInterval = time.Second
+	defaultBatchWriterQueueSize     = 10000
+	defaultBatchWriterMaxAttempts   = 3
+)
+
+type BatchWriterOptions struct {
+	// BatchSize sends a batch as soon as this many revocations are queued.
+	// Defaults to 100.
+	BatchSize int
+	// FlushInterval bounds how long a revocation waits in the queue.
+	// Defaults to 1s.
+	FlushInterval time.Duration
+	// QueueSize bounds the number of buffered revocations; Revoke blocks
+	// when the queue is full. Defaults to 10000.
+	QueueSize int
+	// MaxAttempts is how many times a failed batch is sent before it is
+	// dropped. Client-level retries happen within each attempt. Defaults to 3.
+	MaxAttempts int
+	// OnError is called with every batch that is dropped. Batches larger
+	// than one API call are sent, and dropped, in parts.
+	OnError func(revocations []RevokeRequest, err error)
+	// CallOptions are applied to every batch call.
+	CallOptions []CallOption
+}
+
+// BatchWriter buffers revocations and sends them in background batches, so
+// callers such as logout handlers do not wait on API latency. Call Close on
+// shutdown to send whatever is still queued.
+type BatchWriter struct {
+	client *Client
+	opts   BatchWriterOptions
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan batchItem
+	done   chan struct{}
+	// unregister removes the writer from its client's Close.
+	unregister func()
+
+	// errMu guards err, the first failure since the last Flush.
+	errMu sync.Mutex
+	err   error
+}
+
+// batchItem is either a revocation or, when flushed is set, a flush marker.
+type batchItem struct {
+	req     RevokeRequest
+	flushed chan error
+}
+
+func NewBatchWriter(client *Client, opts BatchWriterOptions) *BatchWriter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchWriterSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultBatchWriterFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultBatchWriterQueueSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultBatchWriterMaxAttempts
+	}
+	w := &BatchWriter{
+		client: client,
+		opts:   opts,
+		queue:  make(chan batchItem, opts.QueueSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	w.unregister = client.lifecycle.register(true, func(ctx context.Context) error {
+		closed := make(chan error, 1)
+		go func() { closed <- w.Close() }()
+		select {
+		case err := <-closed:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	return w
+}
+
+// Revoke queues a revocation. It only blocks when the queue is full, until
+// ctx is done. Invalid revocations are rejected with a *ValidationError
+// right away; send failures are reported by Flush, Close, and OnError.
+func (w *BatchWriter) Revoke(ctx context.Context, req RevokeRequest) error {
+	req, verr := req.withTokenClaims(w.client.expirySkew)
+	if verr != nil {
+		return verr
+	}
+	if err := req.validate(w.client.clock.Now()); err != nil {
+		return err
+	}
+	return w.enqueue(ctx, batchItem{req: req})
+}
+
+// Flush sends everything queued before the call and waits for it to be
+// accepted. It returns the first error since the previous Flush.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	flushed := make(chan error, 1)
+	if err := w.enqueue(ctx, batchItem{flushed: flushed}); err != nil {
+		return err
+	}
+	select {
+	case err := <-flushed:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting revocations, sends the remaining queue, and returns
+// the first error since the previous Flush. Call Flush first to bound the
+// wait with a deadline. Client.Close closes the client's writers.
+func (w *BatchWriter) Close() error {
+	w.unregister()
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+	return w.takeErr()
+}
+
+func (w *BatchWriter) enqueue(ctx context.Context, item batchItem) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return ErrBatchWriterClosed
+	}
+	select {
+	case w.queue <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []RevokeRequest
+	send := func() {
+		if len(batch) > 0 {
+			w.send(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				send()
+				return
+			}
+			if item.flushed != nil {
+				send()
+				item.flushed <- w.takeErr()
+				continue
+			}
+			batch = append(batch, item.req)
+			if len(batch) >= w.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		}
+	}
+}
+
+// send revokes batch in chunks that each fit in one API call.
+func (w *BatchWriter) send(batch []RevokeRequest) {
+	ctx := context.Background()
+	size := w.client.batchSize(ctx, w.opts.CallOptions...)
+	for len(batch) > 0 {
+		chunk := batch[:min(size, len(batch))]
+		batch = batch[len(chunk):]
+		w.sendChunk(ctx, chunk)
+	}
+}
+
+// sendChunk keeps one Idempotency-Key across its attempts, so an attempt
+// whose response was lost is not applied again by the next one.
+func (w *BatchWriter) sendChunk(ctx context.Context, batch []RevokeRequest) {
+	opts := append(w.opts.CallOptions[:len(w.opts.CallOptions):len(w.opts.CallOptions)], WithIdempotencyKey(newIdempotencyKey()))
+	var err error
+	for attempt := 1; attempt <= w.opts.MaxAttempts; attempt++ {
+		if _, err = w.client.Revocations.RevokeBatch(ctx, batch, opts...); err == nil {
+			return
+		}
+		var ce *ClientError
+		if errors.As(err, &ce) && ce.StatusCode < 500 && ce.StatusCode != 429 {
+			break
+		}
+		if attempt < w.opts.MaxAttempts {
+			sleepContext(ctx, w.client.clock, time.Duration(attempt)*time.Second)
+		}
+	}
+
+	w.client.log(ctx, slog.LevelError, "jwtrevoke: dropping revocation batch",
+		"size", len(batch), "error", err)
+	w.errMu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.errMu.Unlock()
+	if w.opts.OnError != nil {
+		w.opts.OnError(batch, err)
+	}
+}
+
+func (w *BatchWriter) takeErr() error {
+	w.errMu.Lock()
+	defer w.errMu.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
@@ -0,0 +1,184 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BillingService reads the account's plan, invoices, and payment status.
+// Amounts are in the smallest unit of Currency, e.g. cents.
+type BillingService struct {
+	client *Client
+}
+
+type Plan struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Interval is "month" or "year".
+	Interval             string    `json:"interval"`
+	Price                int64     `json:"price"`
+	Currency             string    `json:"currency"`
+	RequestLimit         int64     `json:"request_limit"`
+	RevocationEntryLimit int64     `json:"revocation_entry_limit"`
+	CurrentPeriodStart   Timestamp `json:"current_period_start"`
+	CurrentPeriodEnd     Timestamp `json:"current_period_end"`
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceDraft         InvoiceStatus = "draft"
+	InvoiceOpen          InvoiceStatus = "open"
+	InvoicePaid          InvoiceStatus = "paid"
+	InvoiceVoid          InvoiceStatus = "void"
+	InvoiceUncollectible InvoiceStatus = "uncollectible"
+)
+
+type Invoice struct {
+	ID          string        `json:"id"`
+	Number      string        `json:"number"`
+	Status      InvoiceStatus `json:"status"`
+	AmountDue   int64         `json:"amount_due"`
+	AmountPaid  int64         `json:"amount_paid"`
+	Currency    string        `json:"currency"`
+	PeriodStart Timestamp     `json:"period_start"`
+	PeriodEnd   Timestamp     `json:"period_end"`
+	IssuedAt    Timestamp     `json:"issued_at"`
+	DueAt       *Timestamp    `json:"due_at,omitempty"`
+	PaidAt      *Timestamp    `json:"paid_at,omitempty"`
+	// PDFURL downloads the invoice document.
+	PDFURL string        `json:"pdf_url,omitempty"`
+	Lines  []InvoiceLine `json:"lines,omitempty"`
+}
+
+type InvoiceLine struct {
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	Amount      int64  `json:"amount"`
+}
+
+type InvoiceFilter struct {
+	Status InvoiceStatus
+	// From and To bound the invoices' issue dates.
+	From time.Time
+	To   time.Time
+	// Cursor continues a previous listing; use InvoicePage.NextCursor.
+	Cursor string
+	Limit  int
+}
+
+type InvoicePage struct {
+	Invoices   []Invoice `json:"data"`
+	NextCursor string    `json:"next_cursor"`
+}
+
+func (p *InvoicePage) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+func (f InvoiceFilter) values() url.Values {
+	v := url.Values{}
+	if f.Status != "" {
+		v.Set("status", string(f.Status))
+	}
+	setTime(v, "from", f.From)
+	setTime(v, "to", f.To)
+	if f.Cursor != "" {
+		v.Set("cursor", f.Cursor)
+	}
+	if f.Limit > 0 {
+		v.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return v
+}
+
+type PaymentState string
+
+const (
+	PaymentCurrent PaymentState = "current"
+	// PaymentPastDue accounts have an open invoice past its due date.
+	PaymentPastDue PaymentState = "past_due"
+	// PaymentSuspended accounts are restricted until they pay.
+	PaymentSuspended PaymentState = "suspended"
+)
+
+type PaymentStatus struct {
+	State PaymentState `json:"state"`
+	// Balance is owed when positive and credit when negative.
+	Balance       int64          `json:"balance"`
+	Currency      string         `json:"currency"`
+	NextPaymentAt *Timestamp     `json:"next_payment_at,omitempty"`
+	Method        *PaymentMethod `json:"payment_method,omitempty"`
+}
+
+// PaymentMethod describes the card on file without exposing its number.
+type PaymentMethod struct {
+	Brand    string `json:"brand"`
+	Last4    string `json:"last4"`
+	ExpMonth int    `json:"exp_month"`
+	ExpYear  int    `json:"exp_year"`
+}
+
+func (s *BillingService) Plan(ctx context.Context, opts ...CallOption) (*Plan, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/billing/plan", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[Plan](ctx, s.client, req, "plan", opts...)
+}
+
+// ListInvoices returns invoices, newest first.
+func (s *BillingService) ListInvoices(ctx context.Context, filter InvoiceFilter, opts ...CallOption) (*InvoicePage, error) {
+	endpoint := fmt.Sprintf("%s/api/billing/invoices", s.client.baseURL)
+	if query := filter.values().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[InvoicePage](ctx, s.client, req, "", opts...)
+}
+
+// ListAllInvoices follows NextCursor until every invoice matching filter is
+// collected.
+func (s *BillingService) ListAllInvoices(ctx context.Context, filter InvoiceFilter, opts ...CallOption) ([]Invoice, error) {
+	var invoices []Invoice
+	for {
+		page, err := s.ListInvoices(ctx, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, page.Invoices...)
+		if !page.HasMore() {
+			return invoices, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// GetInvoice returns an invoice with its line items.
+func (s *BillingService) GetInvoice(ctx context.Context, invoiceID string, opts ...CallOption) (*Invoice, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/billing/invoices/%s", s.client.baseURL, url.PathEscape(invoiceID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[Invoice](ctx, s.client, req, "invoice", opts...)
+}
+
+func (s *BillingService) PaymentStatus(ctx context.Context, opts ...CallOption) (*PaymentStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/billing/payment", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[PaymentStatus](ctx, s.client, req, "payment", opts...)
+}
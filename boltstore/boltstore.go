@@ -0,0 +1,180 @@
+// Package boltstore provides a bbolt-backed jwtrevokeapi.Store for
+// persisting mirror state on local disk.
+package boltstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	defaultBucket = []byte("jwtrevoke")
+	// formatKey in metaBucket records that every value has an expiry
+	// header.
+	metaBucket = []byte("jwtrevoke.meta")
+	formatKey  = []byte("format")
+)
+
+const currentFormat = 2
+
+var _ jwtrevokeapi.Store = (*Store)(nil)
+
+// Values are stored with an 8-byte big-endian expiry prefix in Unix
+// nanoseconds; zero means the entry does not expire. Databases written
+// before the prefix existed are migrated by New.
+const expiryHeaderLen = 8
+
+type Store struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+// Open opens, creating if necessary, the bbolt database at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return New(db)
+}
+
+// New uses an already open database, storing data in its own bucket.
+func New(db *bolt.DB) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(defaultBucket)
+		if err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if format := meta.Get(formatKey); len(format) == 1 && format[0] >= currentFormat {
+			return nil
+		}
+		if err := migrate(bucket); err != nil {
+			return err
+		}
+		return meta.Put(formatKey, []byte{currentFormat})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db, bucket: defaultBucket}, nil
+}
+
+// migrate adds a no-expiry header to values written without one. Those are
+// JSON or timestamps and so start with a printable byte, while a header
+// starts with zero or the high byte of a Unix nanosecond time.
+func migrate(bucket *bolt.Bucket) error {
+	var keys, values [][]byte
+	err := bucket.ForEach(func(k, v []byte) error {
+		if len(v) > 0 && v[0] >= 0x20 {
+			keys = append(keys, append([]byte(nil), k...))
+			values = append(values, append(make([]byte, expiryHeaderLen), v...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i, k := range keys {
+		if err := bucket.Put(k, values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value, found = decode(tx.Bucket(s.bucket).Get([]byte(key)), time.Now())
+		return nil
+	})
+	return value, found, err
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	encoded := make([]byte, expiryHeaderLen+len(value))
+	if ttl > 0 {
+		binary.BigEndian.PutUint64(encoded, uint64(time.Now().Add(ttl).UnixNano()))
+	}
+	copy(encoded[expiryHeaderLen:], value)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), encoded)
+	})
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+}
+
+func (s *Store) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	p := []byte(prefix)
+	now := time.Now()
+	return s.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(s.bucket).Cursor()
+		for k, v := cur.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = cur.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			value, ok := decode(v, now)
+			if !ok {
+				continue
+			}
+			if err := fn(string(k), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Prune deletes expired entries, which are otherwise only hidden from reads.
+func (s *Store) Prune(ctx context.Context) error {
+	now := time.Now()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		// Deleting while iterating makes a bbolt cursor skip the next key,
+		// so the expired keys are collected first.
+		var expired [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			if _, ok := decode(v, now); !ok {
+				expired = append(expired, append([]byte(nil), k...))
+			}
+			return ctx.Err()
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func decode(raw []byte, now time.Time) ([]byte, bool) {
+	if len(raw) < expiryHeaderLen {
+		return nil, false
+	}
+	if exp := binary.BigEndian.Uint64(raw); exp != 0 && now.UnixNano() >= int64(exp) {
+		return nil, false
+	}
+	return append([]byte(nil), raw[expiryHeaderLen:]...), true
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,750 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultCacheTTL         = time.Minute
+	defaultCacheMaxEntries  = 100000
+	defaultWarmMaxStaleness = 5 * time.Minute
+	maxWarmBackoff          = 30 * time.Second
+	cachePruneInterval      = time.Minute
+)
+
+// ErrRevocationUnavailable is returned, wrapping the underlying error, when
+// revocation status cannot be determined and the failure policy does not
+// allow an answer.
+var ErrRevocationUnavailable = errors.New("jwt-revoke: revocation status unavailable")
+
+type failureMode int
+
+const (
+	failClosed failureMode = iota
+	failOpen
+	failStale
+)
+
+// FailurePolicy decides how a Cache answers when the revocation service
+// cannot be reached.
+type FailurePolicy struct {
+	mode   failureMode
+	maxAge time.Duration
+}
+
+var (
+	// PolicyFailClosed reports ErrRevocationUnavailable, so callers reject
+	// the token. It is the default.
+	PolicyFailClosed = FailurePolicy{mode: failClosed}
+	// PolicyFailOpen treats tokens as not revoked while the service is down.
+	PolicyFailOpen = FailurePolicy{mode: failOpen}
+)
+
+// PolicyStaleCache answers from the last known status of a token if it was
+// fetched within maxAge, and otherwise fails closed.
+func PolicyStaleCache(maxAge time.Duration) FailurePolicy {
+	return FailurePolicy{mode: failStale, maxAge: maxAge}
+}
+
+func (p FailurePolicy) String() string {
+	switch p.mode {
+	case failOpen:
+		return "fail-open"
+	case failStale:
+		return fmt.Sprintf("stale-cache(%s)", p.maxAge)
+	default:
+		return "fail-closed"
+	}
+}
+
+type CacheOptions struct {
+	// TTL is how long a revoked answer is served without asking the API.
+	// Defaults to 1m.
+	TTL time.Duration
+	// NegativeTTL is how long a not-revoked answer is served without asking
+	// the API. Keep it short, since it delays noticing new revocations. Zero
+	// disables negative caching.
+	NegativeTTL time.Duration
+	// StaleWhileRevalidate serves an answer for this long past its TTL while
+	// it is refreshed in the background, so lookups do not wait on a slow
+	// API. Zero always refreshes in the foreground.
+	StaleWhileRevalidate time.Duration
+	// MaxEntries bounds the number of cached tokens. Defaults to 100000.
+	MaxEntries int
+	// Eviction picks which entry to drop when MaxEntries is reached.
+	// Defaults to EvictLRU.
+	Eviction EvictionPolicy
+	// OnEvict is called with the key of every entry dropped to stay within
+	// MaxEntries, e.g. to export a metric. Keys of namespaced views start
+	// with the namespace and a NUL byte, and session keys with a 0x01
+	// byte. It runs with the cache locked and must not call back into it.
+	OnEvict func(jwtID string)
+	// TTLJitter shortens each entry's TTL and NegativeTTL by a random
+	// fraction of up to TTLJitter, e.g. 0.1 for up to 10%, so entries cached
+	// together do not all expire and hit the API together.
+	TTLJitter float64
+	// Policy applies when the API cannot be reached. Defaults to
+	// PolicyFailClosed.
+	Policy FailurePolicy
+	// OnFallback is called every time the policy answers instead of the API,
+	// e.g. to export a metric. For session checks jwtID is "sid:" followed
+	// by the session ID.
+	OnFallback func(jwtID string, outcome FallbackOutcome, err error)
+	// Store lets Warm start from a revocation list on disk, in the format
+	// a Mirror with the same Store persists, and keeps the list Warm
+	// downloads there for the next start.
+	Store Store
+	// WarmMaxStaleness is the oldest list Warm accepts from Store. Its
+	// entries then live for TTL from when Warm loads them, so a revocation
+	// is held at most WarmMaxStaleness plus TTL after the list was taken.
+	// Defaults to 5m.
+	WarmMaxStaleness time.Duration
+	// Clock is the time source for TTLs and expiry. Defaults to the
+	// client's clock when the RevocationAPI is a client's Revocations, else
+	// the system clock.
+	Clock Clock
+}
+
+type FallbackOutcome string
+
+const (
+	FallbackFailedOpen   FallbackOutcome = "failed_open"
+	FallbackFailedClosed FallbackOutcome = "failed_closed"
+	FallbackServedStale  FallbackOutcome = "served_stale"
+)
+
+// CacheStats counts how lookups were answered since the cache was created.
+type CacheStats struct {
+	Lookups int64
+	// Hits includes stale answers served while revalidating.
+	Hits          int64
+	Revalidations int64
+	Errors        int64
+	FailedOpen    int64
+	FailedClosed  int64
+	ServedStale   int64
+	// Coalesced counts misses answered by a lookup another caller already
+	// had in flight for the same token, instead of a call of their own.
+	Coalesced int64
+	// Evictions counts entries dropped to stay within MaxEntries.
+	Evictions int64
+	// Entries is the number of entries cached now. It and Evictions include
+	// every namespace sharing the cache's memory.
+	Entries int
+}
+
+// Cache answers revocation checks from memory where possible and applies a
+// FailurePolicy when the API is unreachable. It works with any
+// RevocationAPI implementation. A Cache built on a client's Revocations also
+// drops entries for tokens in the events the client observes; see
+// OnRevocation.
+type Cache struct {
+	api      RevocationAPI
+	sessions sessionGetter
+	opts     CacheOptions
+	log      func(ctx context.Context, level slog.Level, msg string, args ...any)
+	// clientStats receives hits and misses when the API is a client's
+	// Revocations.
+	clientStats *runtimeCounters
+	// minimize applies the client's WithPIIRedaction to persisted tokens.
+	minimize func(RevokedToken) RevokedToken
+
+	// namespace prefixes the keys of a view created by Namespace.
+	namespace string
+	*cacheState
+	// unsubscribe removes the OnRevocation handler of a Cache on a client's
+	// Revocations.
+	unsubscribe func()
+
+	lookups, hits, revalidations, failures atomic.Int64
+	failedOpen, failedClosed, servedStale  atomic.Int64
+	coalesced                              atomic.Int64
+
+	// health is the outcome of the latest lookup against the API.
+	health struct {
+		sync.Mutex
+		lastErr   error
+		lastErrAt time.Time
+		lastOK    time.Time
+		probedAt  time.Time
+	}
+}
+
+// cacheState is the storage a Cache shares with its namespaced views.
+type cacheState struct {
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	eviction   *evictionQueue
+	lastPrune  time.Time
+	refreshing map[string]bool
+	inflight   map[string]*cacheCall
+	evictions  atomic.Int64
+}
+
+// cacheCall is a lookup in flight, shared by concurrent misses for the same
+// key.
+type cacheCall struct {
+	done      chan struct{}
+	entry     cacheEntry
+	err       error
+	abandoned bool
+}
+
+// sessionGetter looks up session revocations. *RevocationsService and the
+// jwtrevoketest Fake implement it.
+type sessionGetter interface {
+	GetSession(ctx context.Context, sid string, opts ...CallOption) (*RevokedSession, error)
+}
+
+// sessionKeyPrefix keeps cached sessions apart from token IDs. Like
+// namespaceSeparator it is a control character, which a JWT ID cannot
+// start with, so no token ID shares a key with a session.
+const sessionKeyPrefix = "\x01"
+
+type cacheEntry struct {
+	// token is nil when the token was not revoked.
+	token     *RevokedToken
+	fetchedAt time.Time
+	// ttlScale applies TTLJitter; zero means no jitter.
+	ttlScale float64
+}
+
+// ttl scales d by the entry's jitter.
+func (e cacheEntry) ttl(d time.Duration) time.Duration {
+	if e.ttlScale == 0 {
+		return d
+	}
+	return time.Duration(float64(d) * e.ttlScale)
+}
+
+func (e cacheEntry) revoked(now time.Time) bool {
+	if e.token == nil {
+		return false
+	}
+	return e.token.EffectiveAt == nil || !e.token.EffectiveAt.After(now)
+}
+
+func NewCache(api RevocationAPI, opts CacheOptions) *Cache {
+	if opts.TTL <= 0 {
+		opts.TTL = defaultCacheTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultCacheMaxEntries
+	}
+	if opts.WarmMaxStaleness <= 0 {
+		opts.WarmMaxStaleness = defaultWarmMaxStaleness
+	}
+	if opts.Eviction == "" {
+		opts.Eviction = EvictLRU
+	}
+	if opts.Clock == nil {
+		opts.Clock = systemClock{}
+		if s, ok := api.(*RevocationsService); ok {
+			opts.Clock = s.client.clock
+		}
+	}
+	state := &cacheState{
+		entries:    make(map[string]cacheEntry),
+		eviction:   newEvictionQueue(opts.Eviction),
+		refreshing: make(map[string]bool),
+		inflight:   make(map[string]*cacheCall),
+	}
+	return newCacheView(api, opts, "", state)
+}
+
+func newCacheView(api RevocationAPI, opts CacheOptions, namespace string, state *cacheState) *Cache {
+	c := &Cache{api: api, opts: opts, namespace: namespace, cacheState: state, unsubscribe: func() {}}
+	if s, ok := api.(*RevocationsService); ok {
+		client := s.client
+		c.log = client.log
+		c.sessions = s
+		c.clientStats = client.stats
+		c.minimize = client.minimizeToken
+		c.unsubscribe = client.OnRevocation(func(ev RevocationEvent) { c.Invalidate(ev.Token.JwtID) })
+	} else if sessions, ok := api.(sessionGetter); ok {
+		c.sessions = sessions
+	}
+	return c
+}
+
+// Namespace returns a Cache answering from api, typically a client for
+// another project or API key, that shares c's memory: its MaxEntries budget,
+// eviction, and Prune cover both. Keys are kept apart by name, so the same
+// jti in two projects is cached twice. The view uses c's options except
+// Store, so its Warm always loads from api; its lookup counters in Stats are
+// its own. Multi-tenant gateways use it to
+// hold a single bounded cache instead of one per tenant.
+func (c *Cache) Namespace(name string, api RevocationAPI) *Cache {
+	opts := c.opts
+	opts.Store = nil
+	return newCacheView(api, opts, c.namespace+name+namespaceSeparator, c.cacheState)
+}
+
+// Close stops the cache from following its client's revocation events, so
+// a cache that is no longer used can be garbage collected while the client
+// lives on. Views created by Namespace are closed separately. The cache
+// still answers lookups afterwards, but only drops entries when they expire.
+func (c *Cache) Close() {
+	c.unsubscribe()
+}
+
+// namespaceSeparator ends a namespace in cache keys. JWT IDs cannot contain
+// control characters, so keys of different namespaces never collide.
+const namespaceSeparator = "\x00"
+
+// IsRevoked reports whether jwtID is revoked. Errors from the API are
+// resolved by the failure policy; when it fails closed the error wraps
+// ErrRevocationUnavailable.
+func (c *Cache) IsRevoked(ctx context.Context, jwtID string) (bool, error) {
+	return c.isRevoked(ctx, c.namespace+jwtID)
+}
+
+// IsSessionRevoked reports whether the session sid has been revoked, with
+// the same caching and failure policy as IsRevoked.
+func (c *Cache) IsSessionRevoked(ctx context.Context, sid string) (bool, error) {
+	if c.sessions == nil {
+		return false, errors.New("jwt-revoke: the cache's RevocationAPI cannot look up sessions")
+	}
+	return c.isRevoked(ctx, c.namespace+sessionKeyPrefix+sid)
+}
+
+func (c *Cache) isRevoked(ctx context.Context, jwtID string) (bool, error) {
+	c.lookups.Add(1)
+	now := c.opts.Clock.Now()
+
+	c.mu.Lock()
+	entry, cached := c.entries[jwtID]
+	if cached && c.eviction != nil {
+		c.eviction.touch(jwtID)
+	}
+	c.mu.Unlock()
+	if cached {
+		revoked := entry.revoked(now)
+		// Revocations are only ever lifted by an explicit delete, so a
+		// revoked answer is safe to reuse for longer than a negative one.
+		ttl := entry.ttl(c.opts.NegativeTTL)
+		if revoked {
+			ttl = entry.ttl(c.opts.TTL)
+		}
+		age := now.Sub(entry.fetchedAt)
+		if ttl > 0 && age < ttl {
+			c.recordLookup(true)
+			return revoked, nil
+		}
+		if ttl > 0 && age < ttl+c.opts.StaleWhileRevalidate {
+			c.recordLookup(true)
+			c.revalidate(ctx, jwtID)
+			return revoked, nil
+		}
+	}
+	c.recordLookup(false)
+
+	fresh, err := c.fetch(ctx, jwtID)
+	if err != nil {
+		c.failures.Add(1)
+		return c.fallback(ctx, jwtID, entry, cached, now, err)
+	}
+	return fresh.revoked(now), nil
+}
+
+// fetch looks jwtID up and caches the answer. Concurrent misses for the same
+// key share one lookup; if the caller that made it gives up, the others
+// retry rather than fail with its context's error.
+func (c *Cache) fetch(ctx context.Context, jwtID string) (cacheEntry, error) {
+	for {
+		c.mu.Lock()
+		call, ok := c.inflight[jwtID]
+		if !ok {
+			call = &cacheCall{done: make(chan struct{})}
+			c.inflight[jwtID] = call
+		}
+		c.mu.Unlock()
+		if !ok {
+			call.entry, call.err = c.fetchNow(ctx, jwtID)
+			call.abandoned = ctx.Err() != nil
+			c.mu.Lock()
+			delete(c.inflight, jwtID)
+			c.mu.Unlock()
+			close(call.done)
+			return call.entry, call.err
+		}
+
+		c.coalesced.Add(1)
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return cacheEntry{}, ctx.Err()
+		}
+		if !call.abandoned {
+			return call.entry, call.err
+		}
+	}
+}
+
+func (c *Cache) fetchNow(ctx context.Context, jwtID string) (cacheEntry, error) {
+	now := c.opts.Clock.Now()
+	token, err := c.lookup(ctx, jwtID)
+	if errors.Is(err, ErrNotFound) {
+		token, err = nil, nil
+	}
+	if ctx.Err() == nil {
+		c.recordHealth(now, err)
+	}
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	entry := cacheEntry{token: token, fetchedAt: now}
+	c.store(jwtID, entry)
+	return entry, nil
+}
+
+// recordHealth records the outcome of a lookup against the API. Lookups the
+// caller gave up on say nothing about the API and are not recorded.
+func (c *Cache) recordHealth(at time.Time, err error) {
+	c.health.Lock()
+	defer c.health.Unlock()
+	if err != nil {
+		c.health.lastErr, c.health.lastErrAt = err, at
+	} else {
+		c.health.lastOK = at
+	}
+}
+
+// Ready returns nil when lookups are being answered: the latest lookup
+// against the API succeeded, or the failure policy answers without it:
+// always with PolicyFailOpen, and with PolicyStaleCache while the last
+// success is within its maxAge. Otherwise it pings the API, at most every
+// five seconds, since an unready instance receives no lookups that could
+// succeed, and returns the latest failure wrapped in
+// ErrRevocationUnavailable until a lookup or ping succeeds.
+func (c *Cache) Ready() error {
+	if c.opts.Policy.mode == failOpen {
+		return nil
+	}
+	c.health.Lock()
+	lastErr, lastErrAt, lastOK := c.health.lastErr, c.health.lastErrAt, c.health.lastOK
+	c.health.Unlock()
+	if lastErr == nil || lastOK.After(lastErrAt) {
+		return nil
+	}
+	if c.opts.Policy.mode == failStale && c.opts.Clock.Now().Sub(lastOK) <= c.opts.Policy.maxAge {
+		return nil
+	}
+	if probed, err := c.probe(); probed {
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("%w: %w", ErrRevocationUnavailable, lastErr)
+}
+
+const (
+	cacheProbeInterval = 5 * time.Second
+	cacheProbeTimeout  = 2 * time.Second
+)
+
+// pinger checks that the API is reachable. *Client implements it; a Cache
+// on a client's Revocations pings through the client.
+type pinger interface {
+	Ping(ctx context.Context, opts ...CallOption) error
+}
+
+// probe pings the API unless it did so within cacheProbeInterval, and
+// records the outcome like a lookup. probed is false when no ping was sent.
+func (c *Cache) probe() (probed bool, err error) {
+	p, ok := c.api.(pinger)
+	if s, isService := c.api.(*RevocationsService); isService {
+		p, ok = s.client, true
+	}
+	if !ok {
+		return false, nil
+	}
+	now := c.opts.Clock.Now()
+	c.health.Lock()
+	if !c.health.probedAt.IsZero() && now.Sub(c.health.probedAt) < cacheProbeInterval {
+		c.health.Unlock()
+		return false, nil
+	}
+	c.health.probedAt = now
+	c.health.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cacheProbeTimeout)
+	defer cancel()
+	err = p.Ping(ctx, WithCallRetries(0))
+	c.recordHealth(c.opts.Clock.Now(), err)
+	return true, err
+}
+
+// LastError returns the error of the latest failed lookup against the API,
+// or nil if none has failed since the last success.
+func (c *Cache) LastError() error {
+	c.health.Lock()
+	defer c.health.Unlock()
+	if c.health.lastOK.After(c.health.lastErrAt) {
+		return nil
+	}
+	return c.health.lastErr
+}
+
+func (c *Cache) recordLookup(hit bool) {
+	if hit {
+		c.hits.Add(1)
+	}
+	if c.clientStats != nil {
+		c.clientStats.recordCache(hit)
+	}
+}
+
+// lookup fetches a token, or a session when key has sessionKeyPrefix. A
+// revoked session is cached as a token that is always in effect.
+func (c *Cache) lookup(ctx context.Context, key string) (*RevokedToken, error) {
+	key = strings.TrimPrefix(key, c.namespace)
+	sid, ok := strings.CutPrefix(key, sessionKeyPrefix)
+	if !ok || c.sessions == nil {
+		// The Cache keeps entries for its TTL itself; on top of the HTTP
+		// cache a refetch after Invalidate could return the old entry.
+		return c.api.Get(ctx, key, WithCallNoCache())
+	}
+	session, err := c.sessions.GetSession(ctx, sid)
+	if err != nil {
+		return nil, err
+	}
+	return &RevokedToken{Reason: session.Reason, RevokedAt: session.RevokedAt}, nil
+}
+
+// revalidate refreshes jwtID in the background unless a refresh is already
+// running. Failures keep the stale entry, which ages out on its own.
+func (c *Cache) revalidate(ctx context.Context, jwtID string) {
+	c.mu.Lock()
+	if c.refreshing[jwtID] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[jwtID] = true
+	c.mu.Unlock()
+
+	c.revalidations.Add(1)
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, jwtID)
+			c.mu.Unlock()
+		}()
+		if _, err := c.fetch(ctx, jwtID); err != nil {
+			c.failures.Add(1)
+			if c.log != nil {
+				c.log(ctx, slog.LevelWarn, "jwtrevoke: background revalidation failed", "error", err)
+			}
+		}
+	}()
+}
+
+func (c *Cache) fallback(ctx context.Context, jwtID string, entry cacheEntry, cached bool, now time.Time, err error) (bool, error) {
+	var outcome FallbackOutcome
+	var revoked bool
+	switch {
+	case c.opts.Policy.mode == failOpen:
+		outcome = FallbackFailedOpen
+		c.failedOpen.Add(1)
+	case c.opts.Policy.mode == failStale && cached && now.Sub(entry.fetchedAt) <= c.opts.Policy.maxAge:
+		outcome = FallbackServedStale
+		revoked = entry.revoked(now)
+		c.servedStale.Add(1)
+	default:
+		outcome = FallbackFailedClosed
+		c.failedClosed.Add(1)
+	}
+
+	if c.log != nil {
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: revocation check failed, applying policy",
+			"policy", c.opts.Policy.String(), "outcome", string(outcome), "error", err)
+	}
+	if c.opts.OnFallback != nil {
+		key := strings.TrimPrefix(jwtID, c.namespace)
+		if sid, ok := strings.CutPrefix(key, sessionKeyPrefix); ok {
+			key = "sid:" + sid
+		}
+		c.opts.OnFallback(key, outcome, err)
+	}
+	if outcome == FallbackFailedClosed {
+		return false, fmt.Errorf("%w: %w", ErrRevocationUnavailable, err)
+	}
+	return revoked, nil
+}
+
+func (c *Cache) store(jwtID string, entry cacheEntry) {
+	if c.opts.TTLJitter > 0 {
+		entry.ttlScale = 1 - rand.Float64()*min(c.opts.TTLJitter, 1)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[jwtID]; !ok && len(c.entries) >= c.opts.MaxEntries {
+		// Expired revocations go before any live entry is evicted.
+		if c.opts.Clock.Now().Sub(c.lastPrune) >= cachePruneInterval {
+			c.pruneLocked()
+		}
+		if len(c.entries) >= c.opts.MaxEntries {
+			c.evictLocked()
+		}
+	}
+	c.entries[jwtID] = entry
+	if c.eviction != nil {
+		c.eviction.touch(jwtID)
+	}
+}
+
+// Prune drops cached revocations whose expiry date has passed and returns
+// how many it dropped. A full cache prunes at most once a minute before it
+// evicts live entries.
+func (c *Cache) Prune() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pruneLocked()
+}
+
+func (c *Cache) pruneLocked() int {
+	c.lastPrune = c.opts.Clock.Now()
+	pruned := 0
+	for key, entry := range c.entries {
+		if entry.token != nil && entry.token.expiredAt(c.lastPrune) {
+			delete(c.entries, key)
+			if c.eviction != nil {
+				c.eviction.remove(key)
+			}
+			pruned++
+		}
+	}
+	return pruned
+}
+
+func (c *Cache) evictLocked() {
+	var victim string
+	if c.eviction != nil {
+		victim, _ = c.eviction.victim()
+		c.eviction.remove(victim)
+	} else {
+		// Map iteration order is random.
+		for k := range c.entries {
+			victim = k
+			break
+		}
+	}
+	delete(c.entries, victim)
+	c.evictions.Add(1)
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(victim)
+	}
+}
+
+// Warm blocks until the cache holds every revocation in effect, taken from
+// a list no older than WarmMaxStaleness: the one in Store when it is fresh
+// enough, otherwise the full list from the API, retried with backoff until
+// ctx is done. Call it before serving traffic after a deploy, so revoked
+// tokens are answered from memory from the first request, and with
+// PolicyStaleCache also while the API is unreachable. Entries from the API
+// age from the time the list was taken, and entries from Store from the
+// time Warm loads them, so a list older than TTL is not expired on arrival.
+// Lists longer than MaxEntries are only partly loaded.
+func (c *Cache) Warm(ctx context.Context) error {
+	if c.opts.Store != nil {
+		tokens, _, lastSync, ok, err := loadSnapshot(ctx, c.opts.Store)
+		if err != nil && c.log != nil {
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: failed to load persisted revocations", "error", err)
+		}
+		if now := c.opts.Clock.Now(); ok && now.Sub(lastSync) <= c.opts.WarmMaxStaleness {
+			c.warm(tokens, now)
+			return nil
+		}
+	}
+
+	backoff := time.Second
+	for {
+		started := c.opts.Clock.Now()
+		tokens := make(map[string]RevokedToken)
+		err := c.api.Stream(ctx, ListOptions{}, func(t RevokedToken) error {
+			tokens[t.JwtID] = t
+			return nil
+		})
+		if err == nil {
+			c.warm(tokens, started)
+			if c.opts.Store != nil {
+				err = persistSnapshot(ctx, c.opts.Store, tokens, started.Add(-mirrorSyncOverlap), started, c.opts.Clock.Now(), c.minimize)
+				if err != nil && c.log != nil {
+					c.log(ctx, slog.LevelWarn, "jwtrevoke: failed to persist revocations", "error", err)
+				}
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("jwt-revoke: warming cache: %w (last error: %v)", ctx.Err(), err)
+		}
+		if c.log != nil {
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: warming cache failed, retrying", "error", err, "backoff", backoff)
+		}
+		if sleepContext(ctx, c.opts.Clock, backoff) != nil {
+			return fmt.Errorf("jwt-revoke: warming cache: %w (last error: %v)", ctx.Err(), err)
+		}
+		backoff = min(2*backoff, maxWarmBackoff)
+	}
+}
+
+// warm caches tokens as fetched at fetchedAt, skipping expired ones.
+func (c *Cache) warm(tokens map[string]RevokedToken, fetchedAt time.Time) {
+	now := c.opts.Clock.Now()
+	for jwtID, t := range tokens {
+		if t.expiredAt(now) {
+			continue
+		}
+		c.store(c.namespace+jwtID, cacheEntry{token: &t, fetchedAt: fetchedAt})
+	}
+	if c.log != nil {
+		c.log(context.Background(), slog.LevelInfo, "jwtrevoke: cache warmed", "revocations", len(tokens), "as_of", fetchedAt)
+	}
+}
+
+// Invalidate drops the cached status of jwtID, e.g. right after revoking it.
+func (c *Cache) Invalidate(jwtID string) {
+	c.invalidate(c.namespace + jwtID)
+}
+
+// InvalidateSession drops the cached status of the session sid.
+func (c *Cache) InvalidateSession(sid string) {
+	c.invalidate(c.namespace + sessionKeyPrefix + sid)
+}
+
+func (c *Cache) invalidate(jwtID string) {
+	c.mu.Lock()
+	delete(c.entries, jwtID)
+	if c.eviction != nil {
+		c.eviction.remove(jwtID)
+	}
+	c.mu.Unlock()
+}
+
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	entries := len(c.entries)
+	c.mu.Unlock()
+	return CacheStats{
+		Lookups:       c.lookups.Load(),
+		Hits:          c.hits.Load(),
+		Revalidations: c.revalidations.Load(),
+		Errors:        c.failures.Load(),
+		FailedOpen:    c.failedOpen.Load(),
+		FailedClosed:  c.failedClosed.Load(),
+		ServedStale:   c.servedStale.Load(),
+		Coalesced:     c.coalesced.Load(),
+		Evictions:     c.evictions.Load(),
+		Entries:       entries,
+	}
+}
@@ -0,0 +1,98 @@
+package jwtrevokeapi
+
+import "container/heap"
+
+// EvictionPolicy picks the entry a full Cache drops to make room.
+type EvictionPolicy string
+
+const (
+	// EvictLRU drops the least recently used entry. It is the default.
+	EvictLRU EvictionPolicy = "lru"
+	// EvictLFU drops the least frequently used entry, the least recently
+	// used among equals, which keeps hot tokens through bursts of one-off
+	// lookups.
+	EvictLFU EvictionPolicy = "lfu"
+	// EvictRandom drops an arbitrary entry, with no bookkeeping per lookup.
+	EvictRandom EvictionPolicy = "random"
+)
+
+// evictionQueue orders cache keys by eviction priority for EvictLRU and
+// EvictLFU. It is guarded by the cache's mutex.
+type evictionQueue struct {
+	lfu   bool
+	items []*evictionItem
+	byKey map[string]*evictionItem
+	tick  uint64
+}
+
+type evictionItem struct {
+	key      string
+	uses     uint64
+	lastUsed uint64
+	index    int
+}
+
+func newEvictionQueue(policy EvictionPolicy) *evictionQueue {
+	if policy == EvictRandom {
+		return nil
+	}
+	return &evictionQueue{lfu: policy == EvictLFU, byKey: map[string]*evictionItem{}}
+}
+
+// touch records a use of key, adding it when it is new.
+func (q *evictionQueue) touch(key string) {
+	q.tick++
+	if item, ok := q.byKey[key]; ok {
+		item.uses++
+		item.lastUsed = q.tick
+		heap.Fix(q, item.index)
+		return
+	}
+	item := &evictionItem{key: key, uses: 1, lastUsed: q.tick}
+	q.byKey[key] = item
+	heap.Push(q, item)
+}
+
+func (q *evictionQueue) remove(key string) {
+	if item, ok := q.byKey[key]; ok {
+		heap.Remove(q, item.index)
+		delete(q.byKey, key)
+	}
+}
+
+// victim returns the key to evict next.
+func (q *evictionQueue) victim() (string, bool) {
+	if len(q.items) == 0 {
+		return "", false
+	}
+	return q.items[0].key, true
+}
+
+func (q *evictionQueue) Len() int { return len(q.items) }
+
+func (q *evictionQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.lfu && a.uses != b.uses {
+		return a.uses < b.uses
+	}
+	return a.lastUsed < b.lastUsed
+}
+
+func (q *evictionQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *evictionQueue) Push(x any) {
+	item := x.(*evictionItem)
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+func (q *evictionQueue) Pop() any {
+	item := q.items[len(q.items)-1]
+	q.items[len(q.items)-1] = nil
+	q.items = q.items[:len(q.items)-1]
+	return item
+}
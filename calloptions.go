@@ -0,0 +1,197 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CallOption adjusts a single API call without changing the client's defaults.
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	project        string
+	idempotencyKey string
+	dryRun         bool
+	ifNoneMatch    string
+	noCache        bool
+	bulk           bool
+	apiVersion     APIVersion
+	requestID      *string
+	response       *ResponseInfo
+	timeout        time.Duration
+	maxRetries     *int
+	apiKey         string
+	actor          string
+	// longPoll is how long the server may hold the request before it has
+	// anything to return.
+	longPoll time.Duration
+}
+
+// noCallOptions is the configuration of calls made without options. Options
+// only write while newCallConfig applies them, so it can be shared.
+var noCallOptions = &callConfig{}
+
+func newCallConfig(opts []CallOption) *callConfig {
+	if len(opts) == 0 {
+		return noCallOptions
+	}
+	cfg := &callConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ForProject scopes a single call to projectID, overriding WithProject.
+func ForProject(projectID string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.project = projectID
+	}
+}
+
+// WithIdempotencyKey overrides the Idempotency-Key generated for mutating calls,
+// e.g. to reuse a key derived from the caller's own request identifier.
+func WithIdempotencyKey(key string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.idempotencyKey = key
+	}
+}
+
+// WithDryRun asks the API to validate a mutating call, including payload and
+// permissions, without changing any state.
+func WithDryRun() CallOption {
+	return func(cfg *callConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// IfNoneMatch makes a read conditional on the resource having changed since
+// it returned etag. Unchanged resources fail with ErrNotModified.
+func IfNoneMatch(etag string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.ifNoneMatch = etag
+	}
+}
+
+// WithCallTimeout bounds each request of a call, including its retries and
+// reading the response body, by d. It replaces the client's per-request
+// timeout, so it can be shorter for latency-sensitive checks or longer for
+// exports.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithCallRetries overrides WithMaxRetries for a single call. Zero disables
+// retries.
+func WithCallRetries(n int) CallOption {
+	return func(cfg *callConfig) {
+		if n >= 0 {
+			cfg.maxRetries = &n
+		}
+	}
+}
+
+// callContext applies WithCallTimeout, or else the client's
+// WithOperationTimeout, to ctx.
+func (cfg *callConfig) callContext(ctx context.Context, operationTimeout time.Duration) (context.Context, context.CancelFunc) {
+	timeout := cfg.timeout
+	if timeout <= 0 {
+		timeout = operationTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withLongPoll marks a request the server may hold for up to wait.
+func withLongPoll(wait time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.longPoll = wait
+	}
+}
+
+// attemptContext applies WithAttemptTimeout to the context of one attempt,
+// extended by how long a long poll may be held. The timeout covers waiting
+// for the response headers; the third result stops it once they arrive, so
+// a body read after doRequest returns, such as a stream, is not cut off.
+func (c *Client) attemptContext(ctx context.Context, cfg *callConfig) (context.Context, context.CancelFunc, func()) {
+	if c.attemptTimeout <= 0 {
+		return ctx, func() {}, func() {}
+	}
+	attemptCtx, cancelAttempt := context.WithCancel(ctx)
+	timer := time.AfterFunc(c.attemptTimeout+cfg.longPoll, cancelAttempt)
+	return attemptCtx, func() { timer.Stop(); cancelAttempt() }, func() { timer.Stop() }
+}
+
+// cancelBody releases a call's context once its response has been read.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func (c *Client) applyCallOptions(req *http.Request, cfg *callConfig) {
+	req.Header.Set("User-Agent", c.userAgent)
+
+	project := c.project
+	if cfg.project != "" {
+		project = cfg.project
+	}
+	if project != "" {
+		req.Header.Set("X-Project-ID", project)
+	}
+
+	version := c.apiVersion
+	if cfg.apiVersion != "" {
+		version = cfg.apiVersion
+	}
+	c.applyAPIVersion(req, version)
+
+	if c.environment != "" {
+		req.Header.Set("X-JWTRevoke-Environment", string(c.environment))
+	}
+
+	if cfg.dryRun {
+		req.Header.Set("X-Dry-Run", "true")
+	}
+
+	setActor(req, cfg)
+
+	if cfg.ifNoneMatch != "" && req.Method == http.MethodGet {
+		req.Header.Set("If-None-Match", cfg.ifNoneMatch)
+	}
+
+	// The key is fixed before the retry loop so every attempt of one call shares it.
+	if req.Method == http.MethodPost {
+		key := cfg.idempotencyKey
+		if key == "" {
+			key = newIdempotencyKey()
+		}
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+	}
+
+	c.applyHeaders(req)
+}
+
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	var s [32]byte
+	hex.Encode(s[:], b[:])
+	return string(s[:])
+}
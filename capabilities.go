@@ -0,0 +1,138 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// capabilitiesTTL is how long discovered capabilities are trusted before
+	// they are fetched again, e.g. after a plan change.
+	capabilitiesTTL = time.Hour
+	// capabilitiesRetryInterval is how soon a failed discovery is retried.
+	// Until then the SDK assumes every feature is available.
+	capabilitiesRetryInterval = time.Minute
+)
+
+type EventTransport string
+
+const (
+	// TransportLongPoll holds changes requests open until events arrive;
+	// without it, Subscribe polls on an interval.
+	TransportLongPoll EventTransport = "long_poll"
+	TransportPolling  EventTransport = "polling"
+	TransportWebhooks EventTransport = "webhooks"
+)
+
+// Capabilities describes what the API offers the account, which depends on
+// the deployment and the plan.
+type Capabilities struct {
+	// Endpoints lists the available operations as "METHOD /path" patterns,
+	// e.g. "POST /api/revocations/batch".
+	Endpoints []string `json:"endpoints"`
+	// MaxBatchSize is the most revocations accepted by one batch call.
+	MaxBatchSize int `json:"max_batch_size"`
+	// SnapshotFormats lists the edge snapshot formats, e.g. "sorted_hashes"
+	// and "cuckoo".
+	SnapshotFormats []string         `json:"snapshot_formats"`
+	EventTransports []EventTransport `json:"event_transports"`
+	// PollIntervalSeconds is how often to poll for changes when long polling
+	// is not offered.
+	PollIntervalSeconds int `json:"poll_interval_seconds,omitempty"`
+}
+
+func (c *Capabilities) SupportsEndpoint(method, path string) bool {
+	return slices.Contains(c.Endpoints, method+" "+path)
+}
+
+func (c *Capabilities) SupportsTransport(t EventTransport) bool {
+	return slices.Contains(c.EventTransports, t)
+}
+
+func (c *Capabilities) SupportsSnapshotFormat(format string) bool {
+	return slices.Contains(c.SnapshotFormats, format)
+}
+
+// Capabilities fetches what the API offers the account. The SDK discovers
+// this on its own the first time a feature depends on it, and adapts:
+// Subscribe falls back to interval polling without long polling, and
+// RevokeBatch sizes its chunks by MaxBatchSize. Calling Capabilities also
+// refreshes what the SDK uses.
+func (c *Client) Capabilities(ctx context.Context, opts ...CallOption) (*Capabilities, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/capabilities", c.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	caps, err := call[Capabilities](ctx, c, req, "capabilities", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKeyOverride(ctx, newCallConfig(opts)) == "" {
+		c.discovered.set(caps, c.clock.Now().Add(capabilitiesTTL))
+	}
+	return caps, nil
+}
+
+// capabilityState caches discovered capabilities; it is shared with copies
+// made by With.
+type capabilityState struct {
+	mu      sync.Mutex
+	caps    *Capabilities
+	expires time.Time
+}
+
+func (s *capabilityState) set(caps *Capabilities, expires time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.caps, s.expires = caps, expires
+}
+
+// capabilities returns the cached capabilities, discovering them when they
+// are missing or stale. It returns nil when they are unknown, in which case
+// callers behave as if everything were offered. Calls made with another
+// account's key get nil, as the cache holds the client's own.
+func (c *Client) capabilities(ctx context.Context, opts ...CallOption) *Capabilities {
+	if apiKeyOverride(ctx, newCallConfig(opts)) != "" {
+		return nil
+	}
+	c.discovered.mu.Lock()
+	caps, expires := c.discovered.caps, c.discovered.expires
+	c.discovered.mu.Unlock()
+	if c.clock.Now().Before(expires) {
+		return caps
+	}
+
+	caps, err := c.Capabilities(ctx)
+	if err == nil {
+		return caps
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	// Deployments without the discovery endpoint offer everything.
+	retry := capabilitiesRetryInterval
+	var clientErr *ClientError
+	if errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusNotFound {
+		retry = capabilitiesTTL
+	} else {
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: discovering capabilities failed, assuming defaults", "error", err)
+	}
+	c.discovered.set(nil, c.clock.Now().Add(retry))
+	return nil
+}
+
+// batchSize is the number of revocations sent per batch call.
+func (c *Client) batchSize(ctx context.Context, opts ...CallOption) int {
+	if caps := c.capabilities(ctx, opts...); caps != nil && caps.MaxBatchSize > 0 {
+		return caps.MaxBatchSize
+	}
+	return maxRevokeBatchSize
+}
@@ -0,0 +1,74 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventRevoked EventType = "revoked"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+	// EventExpired is sent once a revocation's expiry date passes. The token
+	// has expired too, so copies of the entry can be dropped.
+	EventExpired EventType = "expired"
+)
+
+type RevocationEvent struct {
+	Type       EventType    `json:"type"`
+	Token      RevokedToken `json:"token"`
+	OccurredAt Timestamp    `json:"occurred_at"`
+}
+
+type ChangeSet struct {
+	Events []RevocationEvent `json:"data"`
+	// ServerTime is the point up to which Events is complete; pass it as
+	// since on the next call.
+	ServerTime Timestamp `json:"server_time"`
+}
+
+// Changes returns revocation events that happened after since.
+func (s *RevocationsService) Changes(ctx context.Context, since time.Time, opts ...CallOption) (*ChangeSet, error) {
+	return s.changes(ctx, since, 0, opts...)
+}
+
+// changes asks the server to hold the request for up to wait when there are
+// no events yet.
+func (s *RevocationsService) changes(ctx context.Context, since time.Time, wait time.Duration, opts ...CallOption) (*ChangeSet, error) {
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339Nano))
+	if wait > 0 {
+		params.Set("wait", strconv.Itoa(int(wait/time.Second)))
+		opts = append(opts, withLongPoll(wait))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/revocations/changes?%s", s.client.baseURL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := s.client.readSnapshotBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var result ChangeSet
+	if err := s.client.decodeJSON(bytes.NewReader(body), &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,156 @@
+package jwtrevokeapi
+
+import (
+	"sync"
+	"time"
+)
+
+// CheckStrategy selects how Revocations.IsRevoked answers.
+type CheckStrategy int
+
+const (
+	// CheckDirect asks the API on every check. It is the default.
+	CheckDirect CheckStrategy = iota
+	// HybridPrefilter consults the prefilter set with SetPrefilter first and
+	// only asks the API to confirm tokens it reports, so most checks stay
+	// local while a false positive never rejects a token. Until a prefilter
+	// is set, checks go to the API.
+	HybridPrefilter
+)
+
+const (
+	defaultPrefilterMaxAge  = 10 * time.Minute
+	defaultPrefilterFeedLag = time.Minute
+	// prefilterPruneMin is the size of the recent set at which stale
+	// entries are first pruned.
+	prefilterPruneMin = 64
+)
+
+// Prefilter is a local, probabilistic set of revoked token IDs that may
+// report IDs it does not hold but never misses one it does, such as an
+// edgesnapshot.Set.
+type Prefilter interface {
+	Contains(jwtID string) bool
+}
+
+// WithCheckStrategy sets how Revocations.IsRevoked answers; see
+// CheckStrategy.
+func WithCheckStrategy(strategy CheckStrategy) ClientOption {
+	return func(c *Client) {
+		c.checkStrategy = strategy
+	}
+}
+
+// WithPrefilterMaxAge bounds how stale HybridPrefilter may be before checks
+// go back to the API: the prefilter must have been generated within maxAge,
+// and the event feed reporting what was revoked since must have synced
+// within feedLag. They default to 10 minutes and 1 minute.
+func WithPrefilterMaxAge(maxAge, feedLag time.Duration) ClientOption {
+	return func(c *Client) {
+		if maxAge > 0 {
+			c.prefilterMaxAge = maxAge
+		}
+		if feedLag > 0 {
+			c.prefilterFeedLag = feedLag
+		}
+	}
+}
+
+// SetPrefilter replaces the prefilter consulted under HybridPrefilter, e.g.
+// after downloading a newer edge snapshot. A prefilter only knows what was
+// revoked before it was generated, as reported by its GeneratedAt method or
+// otherwise taken to be when it is set, so it only answers while an event
+// feed covers everything since: a running Mirror or Subscribe on this
+// client that has synced within the WithPrefilterMaxAge feed lag. Tokens
+// this client revokes, and revocations the feed reports, are confirmed with
+// the API until a newer prefilter is set. Without a fresh prefilter and
+// feed, or with a nil prefilter, checks go to the API. The prefilter is
+// shared with copies made by With.
+func (c *Client) SetPrefilter(p Prefilter) {
+	c.prefilter.once.Do(func() {
+		c.OnRevocation(c.prefilter.observe)
+	})
+	c.prefilter.set(p, c.clock.Now(), c.prefilterMaxAge)
+}
+
+// prefilterState holds the prefilter and the token IDs revoked since it was
+// generated, which it cannot know about.
+type prefilterState struct {
+	once        sync.Once
+	mu          sync.Mutex
+	filter      Prefilter
+	generatedAt time.Time
+	recent      map[string]time.Time
+	pruneAt     int
+}
+
+func newPrefilterState() *prefilterState {
+	return &prefilterState{recent: map[string]time.Time{}, pruneAt: prefilterPruneMin}
+}
+
+func (s *prefilterState) set(p Prefilter, now time.Time, maxAge time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filter, s.generatedAt = p, now
+	if g, ok := p.(interface{ GeneratedAt() time.Time }); ok {
+		s.generatedAt = g.GeneratedAt()
+	}
+	s.pruneLocked(now, maxAge)
+}
+
+// pruneLocked drops the revocations a usable prefilter holds: those before
+// it was generated, and those older than maxAge, which every prefilter
+// fresh enough to be consulted was generated after.
+func (s *prefilterState) pruneLocked(now time.Time, maxAge time.Duration) {
+	cutoff := now.Add(-maxAge)
+	if s.filter != nil && s.generatedAt.After(cutoff) {
+		cutoff = s.generatedAt
+	}
+	for jwtID, at := range s.recent {
+		if at.Before(cutoff) {
+			delete(s.recent, jwtID)
+		}
+	}
+	s.pruneAt = max(2*len(s.recent), prefilterPruneMin)
+}
+
+func (s *prefilterState) add(jwtID string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent[jwtID] = at
+}
+
+// prefilterRevoked records that this client revoked jwtID.
+func (c *Client) prefilterRevoked(jwtID string) {
+	if c.checkStrategy == HybridPrefilter {
+		c.prefilter.add(jwtID, c.clock.Now())
+	}
+}
+
+func (s *prefilterState) observe(ev RevocationEvent) {
+	if ev.Type == EventRevoked {
+		s.add(ev.Token.JwtID, ev.OccurredAt.Time)
+	}
+}
+
+// prefilterAbsent reports whether jwtID is known not to be revoked without
+// asking the API.
+func (c *Client) prefilterAbsent(jwtID string) bool {
+	s := c.prefilter
+	now := c.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filter == nil || now.Sub(s.generatedAt) > c.prefilterMaxAge {
+		return false
+	}
+	if !c.events.covers(s.generatedAt, now, c.prefilterFeedLag) {
+		return false
+	}
+	if len(s.recent) >= s.pruneAt {
+		s.pruneLocked(now, c.prefilterMaxAge)
+	}
+	if _, ok := s.recent[jwtID]; ok {
+		return false
+	}
+	return !s.filter.Contains(jwtID)
+}
@@ -0,0 +1,135 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Claims are the bearer token's claims as parsed by Middleware. Times are
+// zero when the claim is absent.
+type Claims struct {
+	JwtID     string
+	Subject   string
+	Issuer    string
+	Audience  []string
+	SessionID string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+	NotBefore time.Time
+
+	// payload is decoded again by Claim, so requests whose handlers never
+	// read custom claims do not pay for them.
+	payload []byte
+}
+
+// Claim decodes the named claim, registered or custom, into v and reports
+// whether it was present.
+func (c *Claims) Claim(name string, v interface{}) (bool, error) {
+	if c.payload == nil {
+		return false, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(c.payload, &raw); err != nil {
+		return false, err
+	}
+	data, ok := raw[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+type (
+	claimsKey           struct{}
+	unverifiedClaimsKey struct{}
+)
+
+// ContextWithClaims returns a copy of ctx carrying verified claims, as
+// Middleware does for requests it lets through once MiddlewareOptions.KeySet
+// has verified their token. It is useful for testing handlers.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the request's token once
+// Middleware has verified its signature with MiddlewareOptions.KeySet.
+// Without a KeySet it reports none.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// UnverifiedClaimsFromContext returns the claims Middleware parsed from the
+// request's token, whether or not their signature was verified. Anyone can
+// forge them, so use them for logging or routing, never for authorization.
+func UnverifiedClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	if claims, ok := ClaimsFromContext(ctx); ok {
+		return claims, ok
+	}
+	claims, ok := ctx.Value(unverifiedClaimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// parsedClaims parses the claims of token, or returns nil when there is no
+// parsable one.
+func parsedClaims(token string) *Claims {
+	if token == "" {
+		return nil
+	}
+	claims, err := claimsFromToken(token)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+// withClaims returns r with claims in its context, for ClaimsFromContext
+// only if verified, or r itself when claims is nil.
+func withClaims(r *http.Request, claims *Claims, verified bool) *http.Request {
+	if claims == nil {
+		return r
+	}
+	if verified {
+		return r.WithContext(ContextWithClaims(r.Context(), claims))
+	}
+	return r.WithContext(context.WithValue(r.Context(), unverifiedClaimsKey{}, claims))
+}
+
+func claimsFromToken(token string) (*Claims, error) {
+	payload, err := tokenPayload(token)
+	if err != nil {
+		return nil, err
+	}
+	return claimsFromPayload(payload)
+}
+
+func claimsFromPayload(payload []byte) (*Claims, error) {
+	var registered struct {
+		tokenClaims
+		SessionID string `json:"sid"`
+	}
+	if err := json.Unmarshal(payload, &registered); err != nil {
+		return nil, err
+	}
+	c := &Claims{
+		JwtID:     registered.JwtID,
+		Subject:   registered.Subject,
+		Issuer:    registered.Issuer,
+		Audience:  registered.Audience,
+		SessionID: registered.SessionID,
+		ExpiresAt: timeOrZero(registered.ExpiresAt),
+		IssuedAt:  timeOrZero(registered.IssuedAt),
+		NotBefore: timeOrZero(registered.NotBefore),
+		payload:   payload,
+	}
+	return c, nil
+}
+
+func timeOrZero(t *Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
@@ -3,33 +3,162 @@ package jwtrevokeapi
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/oauth2"
 )
 
 type ClientOption func(*Client)
 
+// Client is safe for concurrent use by multiple goroutines. Its configuration
+// is fixed once NewClient or With returns; state that changes at runtime,
+// such as failover, fallback keys, and RuntimeStats, is synchronized and
+// shared with copies made by With. Reuse one Client rather than creating one
+// per request, so connections are pooled.
 type Client struct {
-	apiKey            string
-	baseURL           string
-	client            *http.Client
-	maxRetries        int
-	rateLimitDelay    time.Duration
-	requestTimeout    time.Duration
+	credentials         CredentialsProvider
+	tokenSource         oauth2.TokenSource
+	baseURL             string
+	client              *http.Client
+	maxRetries          int
+	rateLimitDelay      time.Duration
+	requestTimeout      time.Duration
+	attemptTimeout      time.Duration
+	httpCache           Store
+	lanes               *priorityLanes
+	operationTimeout    time.Duration
+	logger              *slog.Logger
+	project             string
+	debugWriter         io.Writer
+	fallbackAPIKey      string
+	signingSecret       []byte
+	tlsConfig           *tls.Config
+	pinnedSPKI          map[string]bool
+	proxyURL            *url.URL
+	proxyConfigured     bool
+	dialContext         func(ctx context.Context, network, addr string) (net.Conn, error)
+	tuning              *TransportTuning
+	endpoints           *endpointPool
+	latencyRouting      bool
+	probeInterval       time.Duration
+	snapshotKeys        []ed25519.PublicKey
+	environment         Environment
+	concurrency         int
+	disableCompression  bool
+	maxResponseBytes    int64
+	readTimeout         time.Duration
+	codec               Codec
+	strictDecoding      bool
+	wireFormat          WireFormat
+	apiVersion          APIVersion
+	userAgent           string
+	headers             http.Header
+	contextHeaders      []contextHeader
+	transportMiddleware []func(http.RoundTripper) http.RoundTripper
+	fallback            *atomic.Pointer[fallbackState]
+	lastSecret          *atomic.Pointer[string]
+	stats               *runtimeCounters
+	events              *eventBus
+	lifecycle           *lifecycle
+	rateLimits          *rateLimitState
+	rateLimitThreshold  int
+	onRateLimited       func(ctx context.Context, info RateLimitInfo)
+	onDeprecation       func(ctx context.Context, notice DeprecationNotice)
+	deprecations        *sync.Map
+	adaptivePacing      bool
+	retryBudget         *retryBudget
+	expirySkew          time.Duration
+	tokenSources        []TokenSource
+	tokenKeySet         KeySet
+	postRetries         POSTRetryPolicy
+	retryableStatus     map[int]bool
+	clock               Clock
+	checkStrategy       CheckStrategy
+	prefilter           *prefilterState
+	prefilterMaxAge     time.Duration
+	prefilterFeedLag    time.Duration
+	discovered          *capabilityState
+	piiMode             PIIMode
+	piiKey              []byte
+
+	Revocations   *RevocationsService
+	Webhooks      *WebhooksService
+	AuditLogs     *AuditLogsService
+	APIKeys       *APIKeysService
+	Members       *MembersService
+	Notifications *NotificationsService
+	Billing       *BillingService
+	Privacy       *PrivacyService
 }
 
 type ClientError struct {
 	StatusCode int
 	Message    string
-	Data       interface{}
+	// Code classifies the error; see the CodeXxx constants.
+	Code ErrorCode
+	// FieldErrors lists the rejected fields of an invalid request.
+	FieldErrors []FieldError
+	// DocumentationURL links to a description of the error.
+	DocumentationURL string
+	Data             interface{}
+	// RequestID identifies the failed call for support, see CaptureRequestID.
+	RequestID string
+}
+
+// RetryDeadlineError is returned when a request failed and the next retry
+// could not have been sent before the context deadline. It matches
+// context.DeadlineExceeded as well as the error of the last attempt.
+type RetryDeadlineError struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+	// Wait is the backoff the next retry required, and Remaining the time
+	// that was left until the deadline.
+	Wait      time.Duration
+	Remaining time.Duration
+	Err       error
+}
+
+func (e *RetryDeadlineError) Error() string {
+	return fmt.Sprintf("jwt-revoke: giving up after %d attempts, retry in %s would pass the context deadline (%s left): %v",
+		e.Attempts, e.Wait, e.Remaining.Round(time.Millisecond), e.Err)
+}
+
+func (e *RetryDeadlineError) Unwrap() []error {
+	return []error{context.DeadlineExceeded, e.Err}
 }
 
+var ErrNotFound = errors.New("jwt-revoke: revocation not found")
+
+// ErrNotModified is returned by conditional reads made with IfNoneMatch when
+// the resource has not changed.
+var ErrNotModified = errors.New("jwt-revoke: not modified")
+
 func (e *ClientError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("jwt-revoke error: %s (status: %d, request id: %s)", e.Message, e.StatusCode, e.RequestID)
+	}
 	return fmt.Sprintf("jwt-revoke error: %s (status: %d)", e.Message, e.StatusCode)
 }
 
+// Is lets errors.Is(err, ErrNotFound) match a 404 response.
+func (e *ClientError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
 func WithMaxRetries(retries int) ClientOption {
 	return func(c *Client) {
 		c.maxRetries = retries
@@ -42,116 +171,852 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithAttemptTimeout bounds each attempt of a call, from sending the request
+// to receiving the response headers, by d. Long polls may take d plus the
+// time the server holds them. An attempt whose response has not arrived in
+// time is abandoned and retried like a connection error, so one slow server
+// or connection does not use up the whole call; pair it with
+// WithOperationTimeout to cap the total. Reading the body is bounded by the
+// call's other timeouts and WithReadTimeout.
+// Unlike WithTimeout, it still applies to calls made with WithCallTimeout.
+func WithAttemptTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.attemptTimeout = d
+	}
+}
+
+// WithOperationTimeout bounds every call, all attempts and the waits between
+// them included, by d. Retries that could not be sent in time are not
+// started; see RetryDeadlineError. WithCallTimeout overrides it per call.
+func WithOperationTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.operationTimeout = d
+	}
+}
+
+// WithRateLimitDelay sets how long to wait before retrying a 429 that has
+// no Retry-After header. A Retry-After is honored up to one minute.
 func WithRateLimitDelay(delay time.Duration) ClientOption {
 	return func(c *Client) {
 		c.rateLimitDelay = delay
 	}
 }
 
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithFallbackAPIKey configures a secondary key that is used once the primary
+// key is rejected with a 401, so keys can be rotated without a coordinated redeploy.
+// The primary is used again as soon as its provider returns a different key,
+// and otherwise tried again every five minutes.
+func WithFallbackAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.fallbackAPIKey = apiKey
+	}
+}
+
+// WithProject scopes every request to a project within a multi-tenant account.
+func WithProject(projectID string) ClientOption {
+	return func(c *Client) {
+		c.project = projectID
+	}
+}
+
+// NewClient creates a client authenticating with apiKey. An empty key is
+// resolved through DefaultCredentials instead; WithCredentials overrides both.
 func NewClient(apiKey string, options ...ClientOption) *Client {
+	var credentials CredentialsProvider = StaticCredentials(apiKey)
+	if apiKey == "" {
+		credentials = DefaultCredentials()
+	}
 	c := &Client{
-		apiKey:         apiKey,
-		baseURL:        "https://api.jwtrevoke.com",
-		maxRetries:     3,
-		rateLimitDelay: time.Second,
-		requestTimeout: 10 * time.Second,
-		client:        &http.Client{},
+		credentials:      credentials,
+		baseURL:          productionBaseURL,
+		maxRetries:       3,
+		rateLimitDelay:   time.Second,
+		requestTimeout:   10 * time.Second,
+		concurrency:      defaultConcurrency,
+		probeInterval:    defaultProbeInterval,
+		expirySkew:       defaultExpirySkew,
+		userAgent:        defaultUserAgent,
+		clock:            systemClock{},
+		client:           &http.Client{},
+		fallback:         &atomic.Pointer[fallbackState]{},
+		lastSecret:       &atomic.Pointer[string]{},
+		stats:            newRuntimeCounters(),
+		events:           newEventBus(),
+		lifecycle:        newLifecycle(),
+		rateLimits:       newRateLimitState(),
+		deprecations:     &sync.Map{},
+		prefilter:        newPrefilterState(),
+		prefilterMaxAge:  defaultPrefilterMaxAge,
+		prefilterFeedLag: defaultPrefilterFeedLag,
+		discovered:       &capabilityState{},
 	}
 
+	c.Revocations = &RevocationsService{client: c}
+	c.Webhooks = &WebhooksService{client: c}
+	c.AuditLogs = &AuditLogsService{client: c}
+	c.APIKeys = &APIKeysService{client: c}
+	c.Members = &MembersService{client: c}
+	c.Notifications = &NotificationsService{client: c}
+	c.Billing = &BillingService{client: c}
+	c.Privacy = &PrivacyService{client: c}
+
 	for _, option := range options {
 		option(c)
 	}
 
 	c.client.Timeout = c.requestTimeout
+	c.client.Transport = c.configureTransport(c.client.Transport)
+	if c.debugWriter != nil {
+		c.client.Transport = &debugTransport{next: transportOrDefault(c.client.Transport), w: c.debugWriter, client: c}
+	}
+	for i := len(c.transportMiddleware) - 1; i >= 0; i-- {
+		c.client.Transport = c.transportMiddleware[i](transportOrDefault(c.client.Transport))
+	}
 	return c
 }
 
-func (c *Client) doRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+// With returns a copy of c with options applied on top of its configuration,
+// e.g. a longer timeout for bulk jobs or a different project. The copy
+// shares c's connection pool, fallback key state, rate-limit pacing, and RuntimeStats. Options that shape the
+// transport itself (TLS, pinning, proxies, dialers, tuning, and debug output)
+// only take effect in NewClient; transport middleware wraps the shared
+// transport.
+func (c *Client) With(options ...ClientOption) *Client {
+	clone := *c
+	httpClient := *c.client
+	clone.client = &httpClient
+	clone.headers = c.headers.Clone()
+	clone.tlsConfig = c.tlsConfig.Clone()
+	clone.snapshotKeys = c.snapshotKeys[:len(c.snapshotKeys):len(c.snapshotKeys)]
+	clone.transportMiddleware = c.transportMiddleware[:len(c.transportMiddleware):len(c.transportMiddleware)]
+	clone.contextHeaders = c.contextHeaders[:len(c.contextHeaders):len(c.contextHeaders)]
+
+	clone.Revocations = &RevocationsService{client: &clone}
+	clone.Webhooks = &WebhooksService{client: &clone}
+	clone.AuditLogs = &AuditLogsService{client: &clone}
+	clone.APIKeys = &APIKeysService{client: &clone}
+	clone.Members = &MembersService{client: &clone}
+	clone.Notifications = &NotificationsService{client: &clone}
+	clone.Billing = &BillingService{client: &clone}
+	clone.Privacy = &PrivacyService{client: &clone}
+
+	for _, option := range options {
+		option(&clone)
+	}
+
+	clone.client.Timeout = clone.requestTimeout
+	added := clone.transportMiddleware[len(c.transportMiddleware):]
+	for i := len(added) - 1; i >= 0; i-- {
+		clone.client.Transport = added[i](transportOrDefault(clone.client.Transport))
+	}
+	return &clone
+}
+
+func (c *Client) doRequest(ctx context.Context, req *http.Request, opts ...CallOption) (*http.Response, error) {
 	var resp *http.Response
 	var err error
 
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+	if c.lifecycle.closed.Load() {
+		return nil, ErrClientClosed
+	}
+
+	cfg := newCallConfig(opts)
+	c.applyCallOptions(req, cfg)
+
+	httpClient, maxRetries := c.client, c.maxRetries
+	if cfg.maxRetries != nil {
+		maxRetries = *cfg.maxRetries
+	}
+	ctx, cancel := cfg.callContext(ctx, c.operationTimeout)
+	handedOff := false
+	// releaseAttempt cancels the WithAttemptTimeout context of the latest
+	// attempt and frees its WithPriorityLanes slot.
+	releaseAttempt := func() {}
+	defer func() {
+		releaseAttempt()
+		if !handedOff {
+			cancel()
+		}
+	}()
+	if cfg.timeout > 0 || c.operationTimeout > 0 {
+		req = req.WithContext(ctx)
+	}
+	if cfg.timeout > 0 {
+		// The call's deadline takes over from the client's per-request timeout.
+		withoutTimeout := *c.client
+		withoutTimeout.Timeout = 0
+		httpClient = &withoutTimeout
+	}
+	if !c.disableCompression && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	override := apiKeyOverride(ctx, cfg)
+	if err := c.authenticate(ctx, req, override); err != nil {
+		return nil, err
+	}
+	cacheKey := c.httpCacheKey(req, cfg)
+	if cacheKey != "" && !cfg.noCache {
+		if resp := c.lookupHTTPCache(ctx, cacheKey, req); resp != nil {
+			if cfg.response != nil {
+				*cfg.response = ResponseInfo{StatusCode: resp.StatusCode, Header: resp.Header, RequestID: responseRequestID(resp)}
+			}
+			return resp, nil
+		}
+	}
+	if err := bufferBody(req); err != nil {
+		return nil, err
+	}
+	reauthenticated := false
+	resend := c.resendAllowed(req)
+	var deadlineErr *RetryDeadlineError
+	if c.retryBudget != nil {
+		c.retryBudget.recordRequest()
+	}
+
+	var lane *lane
+	bulk := isBulk(ctx, cfg)
+	if c.lanes != nil {
+		lane = c.lanes.lane(bulk)
+	}
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		releaseAttempt()
+		releaseAttempt = func() {}
 		if attempt > 0 {
-			time.Sleep(time.Duration(attempt) * time.Second)
+			c.stats.retries.Add(1)
+			if err := sleepContext(ctx, c.clock, retryBackoff(attempt)); err != nil {
+				return nil, err
+			}
+		}
+
+		endpointIdx := 0
+		if c.endpoints != nil {
+			var endpoint string
+			endpointIdx, endpoint = c.routeEndpoint()
+			c.rewriteURL(req, endpoint)
+		}
+
+		if c.lanes == nil || bulk {
+			if err := c.pace(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		requestID := setRequestID(ctx, req)
+		if err = c.signRequest(req); err != nil {
+			return nil, err
 		}
 
-		resp, err = c.client.Do(req)
+		// req is only a template: every attempt sends a copy with a fresh
+		// body, since the transport consumes and closes the one it sends.
+		var attemptReq *http.Request
+		if attemptReq, err = newAttempt(req); err != nil {
+			return nil, err
+		}
+		releaseSlot := func() {}
+		if lane != nil {
+			if releaseSlot, err = lane.acquire(ctx, c.clock); err != nil {
+				return nil, err
+			}
+			if cfg.longPoll > 0 {
+				// A held long poll is idle: it counts against the lane's
+				// rate but does not keep one of its slots.
+				releaseSlot()
+				releaseSlot = func() {}
+			}
+		}
+		attemptCtx, cancelAttempt, headersArrived := c.attemptContext(attemptReq.Context(), cfg)
+		releaseAttempt = func() { cancelAttempt(); releaseSlot() }
+		attemptReq = attemptReq.WithContext(attemptCtx)
+		attemptReq, stall := c.watchStall(attemptReq)
+		sent := time.Now()
+		resp, err = httpClient.Do(attemptReq)
+		headersArrived()
 		if err != nil {
+			stall.stop()
+			err = stall.err(err)
+			if attemptCtx.Err() != nil && ctx.Err() == nil {
+				err = fmt.Errorf("jwt-revoke: attempt timed out after %s: %w", c.attemptTimeout, err)
+			}
+			c.stats.transportErrors.Add(1)
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: request failed, retrying",
+				"method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "request_id", requestID, "error", err)
+			c.endpointFailed(ctx, endpointIdx, true)
+			if !resend {
+				break
+			}
+			if ok, deadline := c.retryAllowed(ctx, attempt, maxRetries, 0); !ok {
+				deadlineErr = deadline
+				break
+			}
+			continue
+		}
+		resp.Body = stall.wrap(resp.Body)
+		c.stats.recordStatus(resp.StatusCode)
+		if cfg.response != nil {
+			*cfg.response = ResponseInfo{
+				StatusCode: resp.StatusCode,
+				Header:     resp.Header,
+				RequestID:  responseRequestID(resp),
+				Attempts:   attempt + 1,
+			}
+		}
+		c.observeRateLimit(ctx, resp)
+		c.observeDeprecation(ctx, req, resp)
+		if err = decompressResponse(resp); err != nil {
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: invalid compressed response, retrying",
+				"method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "request_id", requestID, "error", err)
+			if !resend {
+				break
+			}
+			if ok, deadline := c.retryAllowed(ctx, attempt, maxRetries, 0); !ok {
+				deadlineErr = deadline
+				break
+			}
+			continue
+		}
+		resp.Body = c.limitBody(resp.Body)
+
+		if resp.StatusCode == http.StatusUnauthorized && !reauthenticated && override == "" {
+			if key, ok := c.reauthenticate(ctx, req.Header.Get(apiKeyHeader)); ok {
+				drainBody(resp.Body)
+				reauthenticated = true
+				req.Header.Set(apiKeyHeader, key)
+				c.log(ctx, slog.LevelWarn, "jwtrevoke: API key rejected, retrying with refreshed credentials",
+					"method", req.Method, "path", req.URL.Path, "request_id", requestID)
+				attempt--
+				continue
+			}
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && override == "" && c.tokenSource == nil && c.fallbackAPIKey != "" && req.Header.Get(apiKeyHeader) != c.fallbackAPIKey {
+			drainBody(resp.Body)
+			c.fallback.Store(&fallbackState{rejected: req.Header.Get(apiKeyHeader), at: c.clock.Now()})
+			req.Header.Set(apiKeyHeader, c.fallbackAPIKey)
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: primary API key rejected, switching to fallback key",
+				"method", req.Method, "path", req.URL.Path, "request_id", requestID)
+			attempt--
 			continue
 		}
 
 		if resp.StatusCode == http.StatusTooManyRequests {
-			time.Sleep(c.rateLimitDelay)
+			c.stats.rateLimited.Add(1)
+			if isScheduled(ctx) {
+				// The BulkScheduler pauses all of its tasks and retries.
+				return nil, responseError(resp)
+			}
+			delay := c.rateLimitWait(resp)
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: rate limited, backing off",
+				"method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "request_id", requestID, "delay", delay)
+			if ok, deadline := c.retryAllowed(ctx, attempt, maxRetries, delay); !ok {
+				deadlineErr = deadline
+				break
+			}
+			drainBody(resp.Body)
+			releaseAttempt()
+			if err := sleepContext(ctx, c.clock, delay); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
+		if cfg.requestID != nil {
+			*cfg.requestID = responseRequestID(resp)
+		}
+
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			if c.logEnabled(ctx, slog.LevelDebug) {
+				c.log(ctx, slog.LevelDebug, "jwtrevoke: request succeeded",
+					"method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "attempt", attempt+1, "request_id", requestID)
+			}
+			if c.endpoints != nil {
+				c.endpoints.recordSuccess(endpointIdx, time.Since(sent), cfg.longPoll == 0 && !bulk)
+			}
+			if cacheKey != "" {
+				if err := c.storeHTTPCache(ctx, cacheKey, resp); err != nil {
+					return nil, err
+				}
+			}
+			c.invalidateHTTPCache(ctx, req, resp)
+			resp.Body = &drainingBody{ReadCloser: resp.Body}
+			if cfg.timeout > 0 || c.operationTimeout > 0 || c.attemptTimeout > 0 || lane != nil {
+				release := releaseAttempt
+				resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: func() { release(); cancel() }}
+				releaseAttempt = func() {}
+				handedOff = true
+			}
 			return resp, nil
 		}
 
-		if resp.StatusCode >= 500 {
+		if resp.StatusCode == http.StatusNotModified {
+			drainBody(resp.Body)
+			c.log(ctx, slog.LevelDebug, "jwtrevoke: not modified",
+				"method", req.Method, "path", req.URL.Path, "request_id", requestID)
+			return nil, ErrNotModified
+		}
+
+		if c.retryableStatusCode(resp.StatusCode) {
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: server error, retrying",
+				"method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "attempt", attempt+1, "request_id", requestID)
+			if resp.StatusCode >= 500 {
+				c.endpointFailed(ctx, endpointIdx, false)
+			}
+			if !resend {
+				break
+			}
+			if ok, deadline := c.retryAllowed(ctx, attempt, maxRetries, 0); !ok {
+				deadlineErr = deadline
+				break
+			}
+			drainBody(resp.Body)
 			continue
 		}
 
 		// Client error, don't retry
-		var errorResponse struct {
-			Message string      `json:"message"`
-			Data    interface{} `json:"data"`
+		clientErr := responseError(resp)
+		c.log(ctx, slog.LevelDebug, "jwtrevoke: request rejected",
+			"method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "message", clientErr.Message, "request_id", clientErr.RequestID)
+		return nil, clientErr
+	}
+
+	// The last attempt got a 429 or a retryable status rather than a
+	// connection error.
+	if err == nil && resp != nil {
+		err = responseError(resp)
+	}
+	if deadlineErr != nil {
+		deadlineErr.Err = err
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: not retrying, context deadline too close",
+			"method", req.Method, "path", req.URL.Path, "attempts", deadlineErr.Attempts, "remaining", deadlineErr.Remaining, "error", err)
+		return nil, deadlineErr
+	}
+	c.log(ctx, slog.LevelError, "jwtrevoke: retries exhausted",
+		"method", req.Method, "path", req.URL.Path, "error", err)
+	return nil, err
+}
+
+// retryAllowed reports whether the request may be retried after attempt,
+// waiting extra on top of the backoff. Retries that could not be sent before
+// the context deadline are refused with a RetryDeadlineError; otherwise the
+// retry budget, if configured, has the final say.
+func (c *Client) retryAllowed(ctx context.Context, attempt, maxRetries int, extra time.Duration) (bool, *RetryDeadlineError) {
+	if attempt >= maxRetries {
+		return false, nil
+	}
+	wait := extra + retryBackoff(attempt+1)
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := deadline.Sub(c.clock.Now()); remaining <= wait {
+			return false, &RetryDeadlineError{Attempts: attempt + 1, Wait: wait, Remaining: remaining}
+		}
+	}
+	if c.retryBudget != nil && !c.retryBudget.withdraw() {
+		c.stats.retriesShed.Add(1)
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: retry budget exhausted, not retrying")
+		return false, nil
+	}
+	return true, nil
+}
+
+// bufferBody makes req's body replayable for retries. Bodies created from
+// in-memory readers already are; anything else is read into memory once.
+func bufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return nil
+}
+
+// newAttempt copies req for one attempt, with its own headers and body.
+func newAttempt(req *http.Request) (*http.Request, error) {
+	// A shallow copy with its own headers is enough: the SDK only ever
+	// replaces req.URL, never modifies it.
+	attempt := new(http.Request)
+	*attempt = *req
+	attempt.Header = req.Header.Clone()
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
 		}
-		json.NewDecoder(resp.Body).Decode(&errorResponse)
-		return nil, &ClientError{
-			StatusCode: resp.StatusCode,
-			Message:    errorResponse.Message,
-			Data:      errorResponse.Data,
+		attempt.Body = body
+	}
+	return attempt, nil
+}
+
+// drainBody discards what is left of a response the client will not use, up
+// to a limit, so its connection can be reused.
+func drainBody(body io.ReadCloser) {
+	(&drainingBody{ReadCloser: body}).Close()
+}
+
+// drainingBody drains the rest of a response on Close. Decoders often stop
+// short of EOF, and an unread body keeps its connection from being reused.
+type drainingBody struct {
+	io.ReadCloser
+}
+
+// drainBuffers are the scratch space drainingBody reads into, pooled since
+// every call drains a body.
+var drainBuffers = sync.Pool{New: func() any {
+	buf := make([]byte, 4<<10)
+	return &buf
+}}
+
+func (b *drainingBody) Close() error {
+	buf := drainBuffers.Get().(*[]byte)
+	for n := 0; n < len(*buf); {
+		m, err := b.ReadCloser.Read((*buf)[n:])
+		n += m
+		if err != nil {
+			break
 		}
 	}
+	drainBuffers.Put(buf)
+	return b.ReadCloser.Close()
+}
 
-	return resp, err
+// retryBackoff is the wait before the given attempt.
+func retryBackoff(attempt int) time.Duration {
+	return time.Duration(attempt) * time.Second
+}
+
+// responseError decodes the API's error body and closes it.
+func responseError(resp *http.Response) *ClientError {
+	defer resp.Body.Close()
+	var errorResponse errorEnvelope
+	json.NewDecoder(resp.Body).Decode(&errorResponse)
+	return &ClientError{
+		StatusCode:       resp.StatusCode,
+		Message:          errorResponse.Message,
+		Code:             errorResponse.Code,
+		FieldErrors:      errorResponse.Errors,
+		DocumentationURL: errorResponse.DocumentationURL,
+		Data:             errorResponse.Data,
+		RequestID:        responseRequestID(resp),
+	}
 }
 
 type RevokedToken struct {
-	ID            string    `json:"id"`
-	JwtID         string    `json:"jwt_id"`
-	Reason        string    `json:"reason"`
-	ExpiryDate    time.Time `json:"expiry_date"`
-	RevokedByEmail string   `json:"revoked_by_email,omitempty"`
+	ID             string     `json:"id"`
+	JwtID          string     `json:"jwt_id"`
+	Reason         ReasonCode `json:"reason"`
+	ReasonDetail   string     `json:"reason_detail,omitempty"`
+	RevokedAt      Timestamp  `json:"revoked_at"`
+	ExpiryDate     *Timestamp `json:"expiry_date,omitempty"`
+	EffectiveAt    *Timestamp `json:"effective_at,omitempty"`
+	RevokedByEmail string     `json:"revoked_by_email,omitempty"`
+	Metadata       Metadata   `json:"metadata,omitempty"`
+	FamilyID       string     `json:"family_id,omitempty"`
+	KeyID          string     `json:"key_id,omitempty"`
+}
+
+// Permanent reports whether the revocation has no expiry date.
+func (t *RevokedToken) Permanent() bool {
+	return t.ExpiryDate == nil || t.ExpiryDate.IsZero()
+}
+
+// Expired reports whether the revocation's expiry date has passed, after
+// which the token it blocks has expired anyway.
+func (t *RevokedToken) Expired() bool {
+	return t.expiredAt(time.Now())
+}
+
+func (t *RevokedToken) expiredAt(now time.Time) bool {
+	return !t.Permanent() && !t.ExpiryDate.After(now)
+}
+
+// Pending reports whether the revocation is scheduled for a time after now.
+func (t *RevokedToken) Pending() bool {
+	return t.pendingAt(time.Now())
+}
+
+func (t *RevokedToken) pendingAt(now time.Time) bool {
+	return t.EffectiveAt != nil && t.EffectiveAt.After(now)
 }
 
 type RevokeRequest struct {
-	JwtID      string    `json:"jwtId"`
-	Reason     string    `json:"reason"`
-	ExpiryDate time.Time `json:"expiryDate"`
+	JwtID  string     `json:"jwtId"`
+	Reason ReasonCode `json:"reason"`
+	// ReasonDetail is free text to go with Reason, such as a ticket number.
+	ReasonDetail string `json:"reasonDetail,omitempty"`
+	// ExpiryDate is when the revocation entry can be dropped, normally the
+	// token's exp claim; nil keeps it forever.
+	ExpiryDate *time.Time `json:"expiryDate,omitempty"`
+	// EffectiveAt schedules the revocation for a future time; nil revokes immediately.
+	EffectiveAt *time.Time `json:"effectiveAt,omitempty"`
+	// Metadata labels the revocation, for example with a ticket ID or
+	// tenant, and can be filtered on with ListOptions.Labels.
+	Metadata Metadata `json:"metadata,omitempty"`
+	// FamilyID is the refresh-token family the token belongs to, so the
+	// whole chain can later be revoked with RevokeFamily.
+	FamilyID string `json:"familyId,omitempty"`
+	// KeyID is the kid of the key that signed the token, so the revocations
+	// of a compromised key's tokens can be listed with ListOptions.KeyID.
+	KeyID string `json:"keyId,omitempty"`
+	// Token is the encoded JWT being revoked. When set, JwtID defaults to its
+	// jti claim, KeyID to its kid header, and ExpiryDate to its exp claim
+	// plus the client's expiry skew. It is never sent to the API.
+	Token string `json:"-"`
+}
+
+type RevocationStatus string
+
+const (
+	StatusActive  RevocationStatus = "active"
+	StatusPending RevocationStatus = "pending"
+	StatusExpired RevocationStatus = "expired"
+)
+
+type SortField string
+
+const (
+	SortByRevokedAt  SortField = "revoked_at"
+	SortByExpiryDate SortField = "expiry_date"
+	SortByJwtID      SortField = "jwt_id"
+)
+
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+type ListOptions struct {
+	Status RevocationStatus
+	// Reason matches revocations whose reason contains the given substring.
+	Reason         string
+	RevokedByEmail string
+	RevokedAfter   time.Time
+	RevokedBefore  time.Time
+	ExpiresAfter   time.Time
+	ExpiresBefore  time.Time
+	// Labels matches revocations whose metadata has every given key set
+	// to the given value.
+	Labels map[string]string
+	// FamilyID matches revocations in one refresh-token family.
+	FamilyID string
+	// KeyID matches revocations of tokens signed with one key.
+	KeyID     string
+	SortBy    SortField
+	SortOrder SortOrder
+	// Cursor and Limit page through results; see ListRevokedTokensPage.
+	Cursor string
+	Limit  int
+}
+
+type RevocationPage struct {
+	Tokens     []RevokedToken `json:"data"`
+	NextCursor string         `json:"next_cursor"`
+	// ETag identifies the page's contents. Pass it to IfNoneMatch when
+	// fetching the same page again to skip the download if nothing changed.
+	ETag string `json:"-"`
+}
+
+func (p *RevocationPage) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Status != "" {
+		v.Set("status", string(o.Status))
+	}
+	if o.Reason != "" {
+		v.Set("reason", o.Reason)
+	}
+	if o.RevokedByEmail != "" {
+		v.Set("revoked_by_email", o.RevokedByEmail)
+	}
+	setTime(v, "revoked_after", o.RevokedAfter)
+	setTime(v, "revoked_before", o.RevokedBefore)
+	setTime(v, "expires_after", o.ExpiresAfter)
+	setTime(v, "expires_before", o.ExpiresBefore)
+	for _, label := range Metadata(o.Labels).labels() {
+		v.Add("label", label)
+	}
+	if o.FamilyID != "" {
+		v.Set("family_id", o.FamilyID)
+	}
+	if o.KeyID != "" {
+		v.Set("key_id", o.KeyID)
+	}
+	if o.SortBy != "" {
+		v.Set("sort_by", string(o.SortBy))
+	}
+	if o.SortOrder != "" {
+		v.Set("sort_order", string(o.SortOrder))
+	}
+	if o.Cursor != "" {
+		v.Set("cursor", o.Cursor)
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return v
+}
+
+func setTime(v url.Values, key string, t time.Time) {
+	if !t.IsZero() {
+		v.Set(key, t.UTC().Format(time.RFC3339))
+	}
 }
 
-func (c *Client) ListRevokedTokens() ([]RevokedToken, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/revocations/list", c.baseURL), nil)
+type UpdateRequest struct {
+	Reason       *ReasonCode `json:"reason,omitempty"`
+	ReasonDetail *string     `json:"reasonDetail,omitempty"`
+	ExpiryDate   *time.Time  `json:"expiryDate,omitempty"`
+}
+
+// List returns every revocation matching params, following pagination
+// cursors until the list is exhausted.
+func (s *RevocationsService) List(ctx context.Context, params ListOptions, opts ...CallOption) ([]RevokedToken, error) {
+	var tokens []RevokedToken
+	for {
+		page, err := s.ListPage(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, page.Tokens...)
+		if !page.HasMore() {
+			return tokens, nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
+
+// ListPage returns a single page of results. Pass NextCursor back as
+// ListOptions.Cursor to fetch the following page.
+func (s *RevocationsService) ListPage(ctx context.Context, params ListOptions, opts ...CallOption) (*RevocationPage, error) {
+	endpoint := fmt.Sprintf("%s/api/revocations/list", s.client.baseURL)
+	if query := params.values().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
+	s.client.acceptBulk(req)
 
-	req.Header.Set("X-API-Key", c.apiKey)
-	
-	resp, err := c.doRequest(context.Background(), req)
+	resp, err := s.client.doRequest(ctx, req, opts...)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var result struct {
-		Data []RevokedToken `json:"data"`
+	body, err := s.client.readSnapshotBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var page RevocationPage
+	if err := s.client.decodeBulk(bytes.NewReader(body), resp.Header.Get("Content-Type"), &page); err != nil {
+		return nil, err
+	}
+	page.ETag = resp.Header.Get("ETag")
+
+	return &page, nil
+}
+
+func (s *RevocationsService) Get(ctx context.Context, jwtID string, opts ...CallOption) (*RevokedToken, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return nil, err
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/revocations/%s", s.client.baseURL, url.PathEscape(jwtID)), nil)
+	if err != nil {
 		return nil, err
 	}
 
-	return result.Data, nil
+	return call[RevokedToken](ctx, s.client, req, "token", opts...)
+}
+
+// IsRevoked asks the API whether jwtID is currently revoked. Revocations
+// scheduled for the future are not reported as revoked until they take effect.
+// It sends a HEAD request, so no revocation is transferred; use Get for the
+// details.
+func (s *RevocationsService) IsRevoked(ctx context.Context, jwtID string, opts ...CallOption) (bool, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return false, err
+	}
+	if s.client.checkStrategy == HybridPrefilter && s.client.prefilterAbsent(jwtID) {
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", s.client.baseURL+"/api/revocations/"+url.PathEscape(jwtID), nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return s.isRevokedFallback(ctx, jwtID, err, opts)
+	}
+	defer resp.Body.Close()
+
+	return RevocationStatus(resp.Header.Get(revocationStatusHeader)) != StatusPending, nil
+}
+
+// isRevokedFallback handles a failed HEAD check. It is kept out of IsRevoked
+// so the successful path does not allocate for error matching.
+func (s *RevocationsService) isRevokedFallback(ctx context.Context, jwtID string, err error, opts []CallOption) (bool, error) {
+	var clientErr *ClientError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	case errors.As(err, &clientErr) && clientErr.StatusCode == http.StatusMethodNotAllowed:
+		// Deployments without HEAD support answer the full lookup.
+		token, err := s.Get(ctx, jwtID, opts...)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return !token.pendingAt(s.client.clock.Now()), nil
+	default:
+		return false, err
+	}
+}
+
+// revocationStatusHeader carries the status of a revocation in responses to
+// HEAD requests.
+const revocationStatusHeader = "X-Revocation-Status"
+
+// NewRevokeRequest builds a RevokeRequest, leaving ExpiryDate unset when
+// expiryDate is zero.
+func NewRevokeRequest(jwtID string, reason ReasonCode, expiryDate time.Time) RevokeRequest {
+	req := RevokeRequest{JwtID: jwtID, Reason: reason}
+	if !expiryDate.IsZero() {
+		req.ExpiryDate = &expiryDate
+	}
+	return req
 }
 
-func (c *Client) RevokeToken(jwtID string, reason string, expiryDate time.Time) (*RevokedToken, error) {
-	payload := RevokeRequest{
-		JwtID:      jwtID,
-		Reason:     reason,
-		ExpiryDate: expiryDate,
+func (s *RevocationsService) Revoke(ctx context.Context, payload RevokeRequest, opts ...CallOption) (*RevokedToken, error) {
+	payload, verr := payload.withTokenClaims(s.client.expirySkew)
+	if verr != nil {
+		return nil, verr
+	}
+	if err := payload.validate(s.client.clock.Now()); err != nil {
+		return nil, err
 	}
 
 	body, err := json.Marshal(payload)
@@ -159,47 +1024,138 @@ func (c *Client) RevokeToken(jwtID string, reason string, expiryDate time.Time)
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/api/revocations/revoke", c.baseURL), bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/revocations/revoke", s.client.baseURL), bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("X-API-Key", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.doRequest(context.Background(), req)
+	resp, err := s.client.doRequest(ctx, req, opts...)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	s.client.prefilterRevoked(payload.JwtID)
 
-	var result struct {
-		Token RevokedToken `json:"token"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	var token RevokedToken
+	if err := s.client.decodeEnvelope(resp.Body, "token", &token); err != nil {
 		return nil, err
 	}
 
-	return &result.Token, nil
+	return &token, nil
 }
 
-func (c *Client) DeleteRevokedToken(jwtID string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/api/revocations/%s", c.baseURL, jwtID), nil)
+func (s *RevocationsService) Update(ctx context.Context, jwtID string, update UpdateRequest, opts ...CallOption) (*RevokedToken, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return nil, err
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/api/revocations/%s", s.client.baseURL, url.PathEscape(jwtID)), bytes.NewBuffer(body))
 	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[RevokedToken](ctx, s.client, req, "token", opts...)
+}
+
+// DeleteMany deletes several revocations concurrently, bounded by
+// WithConcurrency. The first failure cancels the deletions that have not
+// started yet and is returned; earlier deletions are not rolled back.
+func (s *RevocationsService) DeleteMany(ctx context.Context, jwtIDs []string, opts ...CallOption) error {
+	ctx = ContextWithBulkPriority(ctx)
+	for i, jwtID := range jwtIDs {
+		if err := validateJwtID(fmt.Sprintf("jwtIDs[%d]", i), jwtID); err != nil {
+			return err
+		}
+	}
+
+	g, ctx := newGroup(ctx, s.client.concurrency)
+	for _, jwtID := range jwtIDs {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return s.Delete(ctx, jwtID, opts...)
+		})
+	}
+	return g.Wait()
+}
+
+func (s *RevocationsService) Delete(ctx context.Context, jwtID string, opts ...CallOption) error {
+	return s.DeleteWithOptions(ctx, jwtID, DeleteOptions{}, opts...)
+}
+
+type DeleteOptions struct {
+	// Soft keeps the deleted revocation for the account's retention window,
+	// during which Restore brings it back. It stops blocking the token
+	// either way.
+	Soft bool
+}
+
+func (s *RevocationsService) DeleteWithOptions(ctx context.Context, jwtID string, options DeleteOptions, opts ...CallOption) error {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
 		return err
 	}
 
-	req.Header.Set("X-API-Key", c.apiKey)
+	endpoint := fmt.Sprintf("%s/api/revocations/%s", s.client.baseURL, url.PathEscape(jwtID))
+	if options.Soft {
+		endpoint += "?soft=true"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
 
-	resp, err := c.doRequest(context.Background(), req)
+	resp, err := s.client.doRequest(ctx, req, opts...)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
+	// A dry run reports what would be deleted instead of deleting it.
+	if resp.StatusCode != http.StatusNoContent && !(newCallConfig(opts).dryRun && resp.StatusCode == http.StatusOK) {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// Restore brings back a revocation removed with a soft delete, so it blocks
+// the token again. Revocations past the retention window, or never soft
+// deleted, fail with CodeNotFound.
+func (s *RevocationsService) Restore(ctx context.Context, jwtID string, opts ...CallOption) (*RevokedToken, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/revocations/%s/restore", s.client.baseURL, url.PathEscape(jwtID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	s.client.prefilterRevoked(jwtID)
+
+	var token RevokedToken
+	if err := s.client.decodeEnvelope(resp.Body, "token", &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
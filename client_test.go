@@ -0,0 +1,124 @@
+package jwtrevokeapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/jwtrevoke/go-sdk/jwtrevoketest"
+)
+
+// countConns counts the connections the client dials, as opposed to ones
+// it takes from the idle pool.
+func countConns(dialed *atomic.Int64) jwtrevokeapi.ClientOption {
+	return jwtrevokeapi.WithTransportMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) {
+				if !info.Reused {
+					dialed.Add(1)
+				}
+			}}
+			return next.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+		})
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestClientConcurrentUse shares one client, a client derived from it, and
+// a Cache between many goroutines. Run it with -race.
+func TestClientConcurrentUse(t *testing.T) {
+	srv := jwtrevoketest.NewServer()
+	defer srv.Close()
+	srv.Seed(jwtrevokeapi.RevokeRequest{JwtID: "seeded", Reason: jwtrevokeapi.ReasonLogout})
+
+	const workers, iterations = 16, 25
+	var dialed atomic.Int64
+	client := srv.Client(countConns(&dialed),
+		jwtrevokeapi.WithTransportTuning(jwtrevokeapi.TransportTuning{MaxIdleConnsPerHost: workers}))
+	derived := client.With(jwtrevokeapi.WithTimeout(5 * time.Second))
+	cache := jwtrevokeapi.NewCache(client.Revocations, jwtrevokeapi.CacheOptions{TTL: time.Minute, MaxEntries: 64})
+	defer cache.Close()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				jwtID := fmt.Sprintf("token-%d-%d", w, i)
+				if _, err := client.Revocations.Revoke(ctx, jwtrevokeapi.NewRevokeRequest(jwtID, jwtrevokeapi.ReasonLogout, time.Time{})); err != nil {
+					errs <- fmt.Errorf("Revoke(%s): %w", jwtID, err)
+					return
+				}
+				if revoked, err := derived.Revocations.IsRevoked(ctx, jwtID); err != nil || !revoked {
+					errs <- fmt.Errorf("IsRevoked(%s) = %v, %v; want true", jwtID, revoked, err)
+					return
+				}
+				if revoked, err := cache.IsRevoked(ctx, "seeded"); err != nil || !revoked {
+					errs <- fmt.Errorf("cache.IsRevoked(seeded) = %v, %v; want true", revoked, err)
+					return
+				}
+				if _, err := cache.IsRevoked(ctx, fmt.Sprintf("absent-%d", i)); err != nil {
+					errs <- fmt.Errorf("cache.IsRevoked(absent): %w", err)
+					return
+				}
+				if i%5 == 0 {
+					if _, err := client.Revocations.List(ctx, jwtrevokeapi.ListOptions{}); err != nil {
+						errs <- fmt.Errorf("List: %w", err)
+						return
+					}
+				}
+				_ = cache.Stats()
+				_ = client.RuntimeStats()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Bodies are drained and closed, so the pool serves later requests
+	// instead of dialing a connection per request.
+	if n := dialed.Load(); n > 2*workers {
+		t.Errorf("dialed %d connections for %d workers; responses are not releasing their connections", n, workers)
+	}
+}
+
+// BenchmarkIsRevoked measures a revocation check against a stub transport,
+// so the reported allocations are the SDK's and net/http's request path
+// rather than a server's.
+func BenchmarkIsRevoked(b *testing.B) {
+	header := http.Header{"X-Revocation-Status": {"active"}}
+	client := jwtrevokeapi.NewClient("key",
+		jwtrevokeapi.WithBaseURL("http://jwtrevoke.invalid"),
+		jwtrevokeapi.WithRateLimitDelay(0),
+		jwtrevokeapi.WithHTTPClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: http.NoBody, Request: req}, nil
+		})}),
+	)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		revoked, err := client.Revocations.IsRevoked(ctx, "token_123")
+		if err != nil || !revoked {
+			b.Fatalf("IsRevoked = %v, %v; want true", revoked, err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for retry backoff, rate-limit pacing, cache
+// TTLs, expiry derivation and scheduled revocations. Tests replace it to
+// simulate time instead of sleeping; see jwtrevoketest.Clock.
+type Clock interface {
+	Now() time.Time
+	// After delivers the time on the returned channel once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the clock the client reads the time from and waits on.
+// Caches and Mirrors created for the client use it too. Defaults to the system clock.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// sleepContext waits for d on clock, or until ctx is done, in which case it
+// returns ctx.Err().
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	var wake <-chan time.Time
+	if _, ok := clock.(systemClock); ok {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		wake = timer.C
+	} else {
+		wake = clock.After(d)
+	}
+	select {
+	case <-wake:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
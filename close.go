@@ -0,0 +1,107 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClientClosed is returned by calls made after Client.Close.
+var ErrClientClosed = errors.New("jwt-revoke: client is closed")
+
+// lifecycle tracks a client's background work so Close can shut it down.
+// ctx is cancelled when Close starts, which ends loops tied to it.
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed atomic.Bool
+
+	mu       sync.Mutex
+	closing  bool
+	nextID   int
+	flushers map[int]func(context.Context) error
+	stoppers map[int]func(context.Context) error
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{
+		ctx:      ctx,
+		cancel:   cancel,
+		flushers: make(map[int]func(context.Context) error),
+		stoppers: make(map[int]func(context.Context) error),
+	}
+}
+
+// register adds fn to the work Close waits for. Flushers run first, while
+// requests can still be sent; stoppers run once ctx is cancelled. The
+// returned func removes fn again, for resources that were shut down on
+// their own.
+func (l *lifecycle) register(flush bool, fn func(context.Context) error) (unregister func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	set := l.stoppers
+	if flush {
+		set = l.flushers
+	}
+	id := l.nextID
+	l.nextID++
+	set[id] = fn
+	return func() {
+		l.mu.Lock()
+		delete(set, id)
+		l.mu.Unlock()
+	}
+}
+
+// Close shuts down the client's background work: it flushes BatchWriters,
+// then stops Mirrors, Subscriptions, and endpoint probes, and finally closes
+// idle connections. Calls made afterwards fail with ErrClientClosed. ctx
+// bounds the wait; Close returns its error if it expires first. Clients
+// derived with With share their parent's background work, so closing any
+// of them closes all.
+func (c *Client) Close(ctx context.Context) error {
+	l := c.lifecycle
+	l.mu.Lock()
+	if l.closing {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closing = true
+	flushers := make([]func(context.Context) error, 0, len(l.flushers))
+	for _, fn := range l.flushers {
+		flushers = append(flushers, fn)
+	}
+	stoppers := make([]func(context.Context) error, 0, len(l.stoppers))
+	for _, fn := range l.stoppers {
+		stoppers = append(stoppers, fn)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, fn := range flushers {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	l.cancel()
+	for _, fn := range stoppers {
+		if err := fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	l.closed.Store(true)
+	c.client.CloseIdleConnections()
+	return errors.Join(errs...)
+}
+
+// waitDone waits for done to be closed or ctx to be done.
+func waitDone(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
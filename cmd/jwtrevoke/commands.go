@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+func runRevoke(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("revoke", "[<jwt-id>]")
+	reason := fs.String("reason", "", "revocation reason code, such as compromised or logout")
+	detail := fs.String("detail", "", "free-text detail to record with the reason")
+	rawToken := fs.String("token", "", "the JWT to revoke, or - to read it from stdin; its jti and exp are used")
+	expires := fs.String("expires", "", "when the revocation entry expires, as a duration from now or RFC 3339 time, or \"never\"; defaults to the -token's exp, otherwise never")
+	effective := fs.String("effective-at", "", "schedule the revocation for a later duration or RFC 3339 time")
+	dryRun := fs.Bool("dry-run", false, "validate without revoking")
+	labels := labelsFlag(fs, "label", "attach a key=value metadata label; repeatable")
+	family := fs.String("family", "", "refresh-token family the token belongs to")
+	kid := fs.String("kid", "", "ID of the key that signed the token")
+	out := outputFlag(fs)
+	jwtID, err := parseOptionalArg(fs, args)
+	if err != nil {
+		return err
+	}
+	if *rawToken == "-" {
+		data, err := io.ReadAll(env.stdin)
+		if err != nil {
+			return err
+		}
+		*rawToken = strings.TrimSpace(string(data))
+	}
+	if jwtID == "" && *rawToken == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	req := jwtrevokeapi.RevokeRequest{
+		JwtID:        jwtID,
+		Token:        *rawToken,
+		Reason:       jwtrevokeapi.ReasonCode(*reason),
+		ReasonDetail: *detail,
+		Metadata:     jwtrevokeapi.Metadata(labels),
+		FamilyID:     *family,
+		KeyID:        *kid,
+	}
+	if *expires != "" && *expires != "never" {
+		at, err := parseWhen(*expires)
+		if err != nil {
+			return fmt.Errorf("-expires: %w", err)
+		}
+		req.ExpiryDate = &at
+	}
+	if *effective != "" {
+		at, err := parseWhen(*effective)
+		if err != nil {
+			return fmt.Errorf("-effective-at: %w", err)
+		}
+		req.EffectiveAt = &at
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	var opts []jwtrevokeapi.CallOption
+	if *dryRun {
+		opts = append(opts, jwtrevokeapi.WithDryRun())
+	}
+	token, err := client.Revocations.Revoke(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	return writeTokens(env.stdout, *out, []jwtrevokeapi.RevokedToken{*token})
+}
+
+// runCheck exits with status 0 when the token is not revoked, and 3 when it
+// is, so it can gate shell scripts.
+func runCheck(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("check", "<jwt-id>")
+	out := outputFlag(fs)
+	jwtID, err := parseOneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	revoked, err := client.Revocations.IsRevoked(ctx, jwtID)
+	if err != nil {
+		return err
+	}
+
+	if *out == outputJSON {
+		if err := writeJSON(env.stdout, map[string]interface{}{"jwt_id": jwtID, "revoked": revoked}); err != nil {
+			return err
+		}
+	} else if revoked {
+		fmt.Fprintf(env.stdout, "%s: revoked\n", jwtID)
+	} else {
+		fmt.Fprintf(env.stdout, "%s: not revoked\n", jwtID)
+	}
+	if revoked {
+		return exitError{code: 3}
+	}
+	return nil
+}
+
+func runList(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("list", "")
+	status := fs.String("status", "", "filter by status: active, pending, or expired")
+	reason := fs.String("reason", "", "filter by reason substring")
+	revokedBy := fs.String("revoked-by", "", "filter by the email of the revoking user")
+	sortBy := fs.String("sort", "", "sort by revoked_at, expiry_date, or jwt_id")
+	desc := fs.Bool("desc", false, "sort in descending order")
+	limit := fs.Int("limit", 0, "maximum number of results; 0 lists everything")
+	labels := labelsFlag(fs, "label", "filter by a key=value metadata label; repeatable")
+	family := fs.String("family", "", "filter by refresh-token family")
+	kid := fs.String("kid", "", "filter by the ID of the signing key")
+	out := outputFlag(fs)
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	params := jwtrevokeapi.ListOptions{
+		Status:         jwtrevokeapi.RevocationStatus(*status),
+		Reason:         *reason,
+		RevokedByEmail: *revokedBy,
+		Labels:         labels,
+		FamilyID:       *family,
+		KeyID:          *kid,
+		SortBy:         jwtrevokeapi.SortField(*sortBy),
+	}
+	if *desc {
+		params.SortOrder = jwtrevokeapi.SortDescending
+	}
+
+	var tokens []jwtrevokeapi.RevokedToken
+	if *limit > 0 {
+		params.Limit = *limit
+		page, err := client.Revocations.ListPage(ctx, params)
+		if err != nil {
+			return err
+		}
+		tokens = page.Tokens
+	} else if tokens, err = client.Revocations.List(ctx, params); err != nil {
+		return err
+	}
+	return writeTokens(env.stdout, *out, tokens)
+}
+
+func runDelete(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("delete", "<jwt-id>")
+	dryRun := fs.Bool("dry-run", false, "validate without deleting")
+	jwtID, err := parseOneArg(fs, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	var opts []jwtrevokeapi.CallOption
+	if *dryRun {
+		opts = append(opts, jwtrevokeapi.WithDryRun())
+	}
+	if err := client.Revocations.Delete(ctx, jwtID, opts...); err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Fprintf(env.stdout, "%s: would be deleted\n", jwtID)
+	} else {
+		fmt.Fprintf(env.stdout, "%s: deleted\n", jwtID)
+	}
+	return nil
+}
+
+func runImport(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("import", "[file]")
+	format := fs.String("format", "ndjson", "input format: ndjson or csv")
+	reason := fs.String("reason", "", "reason for records without one")
+	batchSize := fs.Int("batch-size", 0, "records per bulk call")
+	concurrency := fs.Int("concurrency", 0, "bulk calls in flight")
+	dryRun := fs.Bool("dry-run", false, "validate without revoking")
+	out := outputFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	in := env.stdin
+	if name := fs.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	var opts []jwtrevokeapi.CallOption
+	if *dryRun {
+		opts = append(opts, jwtrevokeapi.WithDryRun())
+	}
+	report, err := client.Revocations.Import(ctx, in, jwtrevokeapi.ImportOptions{
+		Format:        jwtrevokeapi.ExportFormat(*format),
+		BatchSize:     *batchSize,
+		Concurrency:   *concurrency,
+		DefaultReason: jwtrevokeapi.ReasonCode(*reason),
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	if err := writeImportReport(env.stdout, *out, report); err != nil {
+		return err
+	}
+	if len(report.Errors) > 0 {
+		return exitError{code: 1}
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("export", "")
+	format := fs.String("format", "ndjson", "output format: ndjson or csv")
+	path := fs.String("o", "-", "output file")
+	checkpoint := fs.String("checkpoint", "", "file recording progress, so an interrupted export to -o resumes where it stopped")
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+	if *checkpoint != "" && *path == "-" {
+		return errors.New("-checkpoint requires -o")
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	if *checkpoint != "" {
+		return exportWithCheckpoint(ctx, client, jwtrevokeapi.ExportFormat(*format), *path, *checkpoint)
+	}
+	w := env.stdout
+	if *path != "-" {
+		f, err := os.Create(*path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return client.Revocations.Export(ctx, w, jwtrevokeapi.ExportFormat(*format))
+}
+
+// exportWithCheckpoint records the cursor and output size after every page.
+// On restart the output is truncated to the recorded size, so the page that
+// was in progress is not written twice.
+func exportWithCheckpoint(ctx context.Context, client *jwtrevokeapi.Client, format jwtrevokeapi.ExportFormat, path, checkpoint string) error {
+	var from jwtrevokeapi.Cursor
+	var offset int64
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if data, err := os.ReadFile(checkpoint); err == nil {
+		var raw string
+		if _, err := fmt.Sscan(string(data), &raw, &offset); err != nil {
+			return fmt.Errorf("reading %s: %w", checkpoint, err)
+		}
+		if from, err = jwtrevokeapi.ParseCursor(raw); err != nil {
+			return fmt.Errorf("reading %s: %w", checkpoint, err)
+		}
+		flags = os.O_WRONLY
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := f.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	return client.Revocations.ExportWithOptions(ctx, f, jwtrevokeapi.ExportOptions{
+		Format: format,
+		From:   from,
+		Checkpoint: func(next jwtrevokeapi.Cursor) error {
+			if next.Done() {
+				return os.Remove(checkpoint)
+			}
+			if err := f.Sync(); err != nil {
+				return err
+			}
+			size, err := f.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			tmp := checkpoint + ".tmp"
+			if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%s %d\n", next, size)), 0o600); err != nil {
+				return err
+			}
+			return os.Rename(tmp, checkpoint)
+		},
+	})
+}
+
+// runWatch polls the change feed and prints each event until interrupted.
+func runWatch(ctx context.Context, env *cliEnv, args []string) error {
+	fs := env.flagSet("watch", "")
+	interval := fs.Duration("interval", 5*time.Second, "poll interval")
+	since := fs.String("since", "", "replay changes after this duration ago or RFC 3339 time")
+	out := outputFlag(fs)
+	if err := parseNoArgs(fs, args); err != nil {
+		return err
+	}
+
+	client, err := env.client()
+	if err != nil {
+		return err
+	}
+	cursor := time.Now()
+	if *since != "" {
+		if cursor, err = parseSince(*since); err != nil {
+			return fmt.Errorf("-since: %w", err)
+		}
+	}
+
+	events := newEventWriter(env.stdout, *out)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		set, err := client.Revocations.Changes(ctx, cursor)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(env.stderr, "jwtrevoke: %v\n", err)
+		} else {
+			for _, ev := range set.Events {
+				if err := events.write(ev); err != nil {
+					return err
+				}
+			}
+			cursor = set.ServerTime.Time
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseOneArg parses flags on either side of a single positional argument,
+// so "check abc -output json" works as well as "check -output json abc".
+func parseOneArg(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return "", errUsage
+	}
+	arg := fs.Arg(0)
+	if err := parseNoArgs(fs, fs.Args()[1:]); err != nil {
+		return "", err
+	}
+	return arg, nil
+}
+
+// parseOptionalArg is parseOneArg for commands whose argument may be
+// replaced by a flag; it returns "" when there is none.
+func parseOptionalArg(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() == 0 {
+		return "", nil
+	}
+	arg := fs.Arg(0)
+	if err := parseNoArgs(fs, fs.Args()[1:]); err != nil {
+		return "", err
+	}
+	return arg, nil
+}
+
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// labelsFlag defines a repeatable key=value flag collected into a map.
+func labelsFlag(fs *flag.FlagSet, name, usage string) map[string]string {
+	labels := map[string]string{}
+	fs.Func(name, usage, func(v string) error {
+		key, value, err := jwtrevokeapi.ParseLabel(v)
+		if err != nil {
+			return err
+		}
+		labels[key] = value
+		return nil
+	})
+	return labels
+}
+
+// parseWhen accepts a duration from now, such as "720h", or an RFC 3339 time.
+func parseWhen(v string) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.New("want a duration such as 24h or an RFC 3339 time")
+	}
+	return t, nil
+}
+
+// parseSince is parseWhen for the past: durations count back from now.
+func parseSince(v string) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return parseWhen(v)
+}
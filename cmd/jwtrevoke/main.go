@@ -0,0 +1,128 @@
+// Command jwtrevoke manages token revocations from the shell.
+//
+// Usage:
+//
+//	jwtrevoke <command> [flags] [args]
+//
+// Configuration comes from the environment and ~/.jwtrevoke/config.yaml as
+// described for jwtrevokeapi.NewClientFromEnv.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+const usage = `Usage: jwtrevoke <command> [flags] [args]
+
+Commands:
+  revoke <jwt-id>   revoke a token
+  check <jwt-id>    report whether a token is revoked
+  list              list revoked tokens
+  delete <jwt-id>   remove a revocation
+  import [file]     bulk revoke from NDJSON or CSV (stdin by default)
+  export            write the revocation list as NDJSON or CSV
+  watch             stream revocation changes
+
+Run "jwtrevoke <command> -h" for the flags of a command.
+
+Environment:
+  JWTREVOKE_API_KEY       API key (or api_key in the config file profile)
+  JWTREVOKE_BASE_URL      API base URL
+  JWTREVOKE_PROJECT       project ID sent with every request
+  JWTREVOKE_ENVIRONMENT   "production" or "sandbox"
+  JWTREVOKE_TIMEOUT       request timeout, e.g. "30s"
+  JWTREVOKE_PROFILE       profile in ~/.jwtrevoke/config.yaml, "default" if unset
+`
+
+type command func(ctx context.Context, env *cliEnv, args []string) error
+
+var commands = map[string]command{
+	"revoke": runRevoke,
+	"check":  runCheck,
+	"list":   runList,
+	"delete": runDelete,
+	"import": runImport,
+	"export": runExport,
+	"watch":  runWatch,
+}
+
+type cliEnv struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errUsage signals that the usage or flag help was already printed.
+var errUsage = errors.New("usage")
+
+// exitError carries a non-zero exit status without an error message, e.g.
+// check reporting a revoked token.
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	env := &cliEnv{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(run(ctx, env, os.Args[1:]))
+}
+
+func run(ctx context.Context, env *cliEnv, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(env.stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.stderr, "jwtrevoke: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	err := cmd(ctx, env, args[1:])
+	var exit exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return 2
+	case errors.As(err, &exit):
+		return exit.code
+	default:
+		fmt.Fprintf(env.stderr, "jwtrevoke: %v\n", err)
+		return 1
+	}
+}
+
+// client builds an SDK client from the environment and config file.
+func (env *cliEnv) client() (*jwtrevokeapi.Client, error) {
+	client, err := jwtrevokeapi.NewClientFromEnv(jwtrevokeapi.WithAppInfo("jwtrevoke-cli", ""))
+	if errors.Is(err, jwtrevokeapi.ErrNoCredentials) {
+		return nil, errors.New("JWTREVOKE_API_KEY is not set and the config file profile has no api_key")
+	}
+	return client, err
+}
+
+func (env *cliEnv) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Usage: jwtrevoke %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
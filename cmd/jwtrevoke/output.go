@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("output", outputTable, "output format: table or json")
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeTokens(w io.Writer, format string, tokens []jwtrevokeapi.RevokedToken) error {
+	if format == outputJSON {
+		if tokens == nil {
+			tokens = []jwtrevokeapi.RevokedToken{}
+		}
+		return writeJSON(w, tokens)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "JWT ID\tREASON\tREVOKED AT\tEFFECTIVE AT\tEXPIRES")
+	for _, t := range tokens {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.JwtID, t.Reason, formatTime(t.RevokedAt), formatTimePtr(t.EffectiveAt), formatTimePtr(t.ExpiryDate))
+	}
+	return tw.Flush()
+}
+
+func writeImportReport(w io.Writer, format string, report *jwtrevokeapi.ImportReport) error {
+	if format == outputJSON {
+		type importError struct {
+			Line  int    `json:"line"`
+			JwtID string `json:"jwt_id,omitempty"`
+			Error string `json:"error"`
+		}
+		errs := make([]importError, 0, len(report.Errors))
+		for _, e := range report.Errors {
+			errs = append(errs, importError{Line: e.Line, JwtID: e.JwtID, Error: e.Err.Error()})
+		}
+		return writeJSON(w, map[string]interface{}{
+			"total":    report.Total,
+			"imported": report.Imported,
+			"errors":   errs,
+		})
+	}
+
+	fmt.Fprintf(w, "imported %d of %d records\n", report.Imported, report.Total)
+	for _, e := range report.Errors {
+		fmt.Fprintf(w, "  %v\n", e)
+	}
+	return nil
+}
+
+// eventWriter prints change events as they arrive. JSON output is one object
+// per line so it can be piped into jq.
+type eventWriter struct {
+	w      io.Writer
+	format string
+	enc    *json.Encoder
+}
+
+func newEventWriter(w io.Writer, format string) *eventWriter {
+	return &eventWriter{w: w, format: format, enc: json.NewEncoder(w)}
+}
+
+func (ew *eventWriter) write(ev jwtrevokeapi.RevocationEvent) error {
+	if ew.format == outputJSON {
+		return ew.enc.Encode(ev)
+	}
+	_, err := fmt.Fprintf(ew.w, "%s  %-8s %s  %s\n", formatTime(ev.OccurredAt), ev.Type, ev.Token.JwtID, ev.Token.Reason)
+	return err
+}
+
+func formatTime(t jwtrevokeapi.Timestamp) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *jwtrevokeapi.Timestamp) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
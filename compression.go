@@ -0,0 +1,84 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// gzipRequestThreshold is the body size from which bulk requests are sent
+// compressed; smaller bodies are not worth the CPU.
+const gzipRequestThreshold = 4 * 1024
+
+// WithCompression controls gzip compression. When enabled, the default,
+// responses are requested with Accept-Encoding: gzip and decompressed
+// transparently, and large bulk request bodies are compressed.
+func WithCompression(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.disableCompression = !enabled
+	}
+}
+
+// compressRequest gzips body into req when it is large enough.
+func (c *Client) compressRequest(req *http.Request, body []byte) error {
+	if c.disableCompression || len(body) < gzipRequestThreshold {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	compressed := buf.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.ContentLength = int64(len(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	return nil
+}
+
+// decompressResponse replaces a gzip-encoded body with its decoded stream.
+// Setting Accept-Encoding ourselves turns off http.Transport's own
+// decompression, and custom transports may not have any.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if errors.Is(err, io.EOF) {
+		resp.Body.Close()
+		resp.Body = http.NoBody
+		resp.Header.Del("Content-Encoding")
+		return nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,28 @@
+package jwtrevokeapi
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultConcurrency = 4
+
+// WithConcurrency bounds the number of requests a bulk operation, such as a
+// large RevokeBatch, DeleteRevokedTokens, or Import, keeps in flight.
+// Defaults to 4.
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// newGroup returns an errgroup that runs at most limit tasks at once. The
+// first error cancels the returned context and is returned by Wait.
+func newGroup(ctx context.Context, limit int) (*errgroup.Group, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(limit)
+	return g, ctx
+}
@@ -0,0 +1,169 @@
+package jwtrevokeapi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is one named section of the config file, e.g.
+//
+//	default:
+//	  api_key: your_api_key_here
+//	staging:
+//	  environment: sandbox
+//	  project: proj_web
+//	  timeout: 30s
+type Profile struct {
+	APIKey      string        `yaml:"api_key"`
+	BaseURL     string        `yaml:"base_url"`
+	Environment Environment   `yaml:"environment"`
+	Project     string        `yaml:"project"`
+	APIVersion  APIVersion    `yaml:"api_version"`
+	Timeout     time.Duration `yaml:"timeout"`
+	MaxRetries  *int          `yaml:"max_retries"`
+}
+
+var ErrProfileNotFound = errors.New("jwt-revoke: profile not found")
+
+// DefaultConfigPath is ~/.jwtrevoke/config.yaml, or "" when there is no
+// home directory.
+func DefaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".jwtrevoke", "config.yaml")
+}
+
+// configPath is JWTREVOKE_CONFIG_FILE, or DefaultConfigPath if unset.
+func configPath() string {
+	return cmp.Or(os.Getenv("JWTREVOKE_CONFIG_FILE"), DefaultConfigPath())
+}
+
+// LoadProfile reads the named profile from the YAML config file at path.
+func LoadProfile(path, name string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profiles map[string]Profile
+	if err := yaml.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("jwt-revoke: parsing %s: %w", path, err)
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, path)
+	}
+	return &profile, nil
+}
+
+// NewClientFromEnv builds a client from the environment and the config
+// file, so applications need no code-level configuration:
+//
+//	JWTREVOKE_API_KEY       API key
+//	JWTREVOKE_BASE_URL      API base URL
+//	JWTREVOKE_ENVIRONMENT   "production" or "sandbox"
+//	JWTREVOKE_PROJECT       project ID sent with every request
+//	JWTREVOKE_API_VERSION   API version, e.g. "v2"
+//	JWTREVOKE_TIMEOUT       request timeout, e.g. "30s" or "30"
+//	JWTREVOKE_MAX_RETRIES   maximum retry attempts
+//	JWTREVOKE_PROFILE       config file profile, "default" if unset
+//	JWTREVOKE_CONFIG_FILE   config file, DefaultConfigPath if unset
+//
+// Environment variables override the profile, and options override both.
+// The API key falls back to the profile through FileCredentials, so a key
+// rotated in the file is picked up; if neither has one, ErrNoCredentials is
+// returned.
+func NewClientFromEnv(options ...ClientOption) (*Client, error) {
+	path := configPath()
+	name := os.Getenv("JWTREVOKE_PROFILE")
+	profile, err := LoadProfile(path, cmp.Or(name, "default"))
+	switch {
+	case err == nil:
+	case name == "" && (errors.Is(err, os.ErrNotExist) || errors.Is(err, ErrProfileNotFound)):
+		// Without an explicit profile the config file is optional.
+		profile = &Profile{}
+	default:
+		return nil, err
+	}
+	if err := profile.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	credentials := CredentialsChain{EnvCredentials{}, FileCredentials{Path: path, Profile: cmp.Or(name, "default")}}
+	if _, err := credentials.APIKey(context.Background()); err != nil {
+		return nil, err
+	}
+	base := append(profile.options(), WithCredentials(credentials))
+	return NewClient("", append(base, options...)...), nil
+}
+
+func (p *Profile) applyEnv() error {
+	if v := os.Getenv("JWTREVOKE_BASE_URL"); v != "" {
+		p.BaseURL = v
+	}
+	if v := os.Getenv("JWTREVOKE_ENVIRONMENT"); v != "" {
+		p.Environment = Environment(v)
+	}
+	if v := os.Getenv("JWTREVOKE_PROJECT"); v != "" {
+		p.Project = v
+	}
+	if v := os.Getenv("JWTREVOKE_API_VERSION"); v != "" {
+		p.APIVersion = APIVersion(v)
+	}
+	if v := os.Getenv("JWTREVOKE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if secs, serr := strconv.Atoi(v); serr == nil {
+			d, err = time.Duration(secs)*time.Second, nil
+		}
+		if err != nil {
+			return fmt.Errorf("jwt-revoke: invalid JWTREVOKE_TIMEOUT %q", v)
+		}
+		p.Timeout = d
+	}
+	if v := os.Getenv("JWTREVOKE_MAX_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("jwt-revoke: invalid JWTREVOKE_MAX_RETRIES %q", v)
+		}
+		p.MaxRetries = &n
+	}
+	switch p.Environment {
+	case "", EnvironmentProduction, EnvironmentSandbox:
+	default:
+		return fmt.Errorf("jwt-revoke: unknown environment %q", p.Environment)
+	}
+	return nil
+}
+
+func (p *Profile) options() []ClientOption {
+	var options []ClientOption
+	if p.Environment != "" {
+		options = append(options, WithEnvironment(p.Environment))
+	}
+	if p.BaseURL != "" {
+		options = append(options, WithBaseURL(strings.TrimSpace(p.BaseURL)))
+	}
+	if p.Project != "" {
+		options = append(options, WithProject(p.Project))
+	}
+	if p.APIVersion != "" {
+		options = append(options, WithAPIVersion(p.APIVersion))
+	}
+	if p.Timeout > 0 {
+		options = append(options, WithTimeout(p.Timeout))
+	}
+	if p.MaxRetries != nil {
+		options = append(options, WithMaxRetries(*p.MaxRetries))
+	}
+	return options
+}
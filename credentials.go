@@ -0,0 +1,267 @@
+package jwtrevokeapi
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials is returned when no provider in the chain has an API key.
+var ErrNoCredentials = errors.New("jwt-revoke: no API key found")
+
+// CredentialsProvider supplies the API key for each request. Providers are
+// called once per call, so implementations that fetch keys remotely should
+// be wrapped in NewCachingCredentials.
+type CredentialsProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// WithCredentials resolves the API key through p instead of the key passed
+// to NewClient.
+func WithCredentials(p CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.credentials = p
+	}
+}
+
+// CredentialsFunc adapts a function to CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (string, error)
+
+func (f CredentialsFunc) APIKey(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticCredentials is a fixed API key.
+type StaticCredentials string
+
+func (s StaticCredentials) APIKey(context.Context) (string, error) {
+	if s == "" {
+		return "", ErrNoCredentials
+	}
+	return string(s), nil
+}
+
+// EnvCredentials reads the API key from the environment variable Name,
+// JWTREVOKE_API_KEY by default, on every call.
+type EnvCredentials struct {
+	Name string
+}
+
+func (e EnvCredentials) APIKey(context.Context) (string, error) {
+	name := e.Name
+	if name == "" {
+		name = "JWTREVOKE_API_KEY"
+	}
+	if key := os.Getenv(name); key != "" {
+		return key, nil
+	}
+	return "", ErrNoCredentials
+}
+
+// FileCredentials reads the api_key of a profile in the YAML config file
+// that NewClientFromEnv loads, on every call. Path defaults to
+// JWTREVOKE_CONFIG_FILE, then DefaultConfigPath; Profile defaults to
+// JWTREVOKE_PROFILE, then "default".
+type FileCredentials struct {
+	Path    string
+	Profile string
+}
+
+func (f FileCredentials) APIKey(context.Context) (string, error) {
+	path := cmp.Or(f.Path, configPath())
+	if path == "" {
+		return "", ErrNoCredentials
+	}
+	profile, err := LoadProfile(path, cmp.Or(f.Profile, os.Getenv("JWTREVOKE_PROFILE"), "default"))
+	if errors.Is(err, os.ErrNotExist) || errors.Is(err, ErrProfileNotFound) {
+		return "", ErrNoCredentials
+	}
+	if err != nil {
+		return "", err
+	}
+	if profile.APIKey == "" {
+		return "", ErrNoCredentials
+	}
+	return profile.APIKey, nil
+}
+
+// CredentialsChain returns the key from the first provider that has one.
+// Providers reporting ErrNoCredentials are skipped; any other error stops
+// the chain.
+type CredentialsChain []CredentialsProvider
+
+func (chain CredentialsChain) APIKey(ctx context.Context) (string, error) {
+	for _, p := range chain {
+		key, err := p.APIKey(ctx)
+		if err == nil {
+			return key, nil
+		}
+		if !errors.Is(err, ErrNoCredentials) {
+			return "", err
+		}
+	}
+	return "", ErrNoCredentials
+}
+
+// Invalidate passes through to providers in the chain that cache keys.
+func (chain CredentialsChain) Invalidate() {
+	for _, p := range chain {
+		if inv, ok := p.(invalidator); ok {
+			inv.Invalidate()
+		}
+	}
+}
+
+// DefaultCredentials is the chain NewClient uses when given an empty key:
+// the JWTREVOKE_API_KEY environment variable, then the profile in the
+// config file.
+func DefaultCredentials() CredentialsChain {
+	return CredentialsChain{EnvCredentials{}, FileCredentials{}}
+}
+
+// CachingCredentials remembers the key from another provider for a TTL.
+// If a refresh fails the previous key keeps being used, and a 401 from the
+// API drops the cached key so a rotated key is picked up immediately.
+type CachingCredentials struct {
+	provider CredentialsProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	key     string
+	fetched time.Time
+}
+
+func NewCachingCredentials(provider CredentialsProvider, ttl time.Duration) *CachingCredentials {
+	return &CachingCredentials{provider: provider, ttl: ttl}
+}
+
+func (c *CachingCredentials) APIKey(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && time.Since(c.fetched) < c.ttl {
+		return c.key, nil
+	}
+	key, err := c.provider.APIKey(ctx)
+	if err != nil {
+		if c.key != "" && !c.fetched.IsZero() {
+			return c.key, nil
+		}
+		return "", err
+	}
+	c.key, c.fetched = key, time.Now()
+	return key, nil
+}
+
+// Invalidate forces the next APIKey call to ask the underlying provider.
+func (c *CachingCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetched = time.Time{}
+}
+
+type invalidator interface {
+	Invalidate()
+}
+
+// apiKeyHeader is X-API-Key in canonical form, so setting and reading it
+// does not allocate.
+const apiKeyHeader = "X-Api-Key"
+
+type apiKeyKey struct{}
+
+// WithAPIKeyOverride authenticates a single call with key instead of the
+// client's credentials, so a multi-tenant proxy holding many customers' keys
+// can serve them all through one client and connection pool. Fallback keys
+// and credential refreshes do not apply to it.
+func WithAPIKeyOverride(key string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.apiKey = key
+	}
+}
+
+// ContextWithAPIKey makes calls made with ctx authenticate with key, like
+// WithAPIKeyOverride, including those the SDK makes on the caller's behalf,
+// such as a Cache's lookups. A Cache shares its answers between keys, so
+// give each tenant its own.
+func ContextWithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+// apiKeyOverride returns the key a call overrides the client's credentials
+// with, if any. The call option wins over ctx.
+func apiKeyOverride(ctx context.Context, cfg *callConfig) string {
+	if cfg.apiKey != "" {
+		return cfg.apiKey
+	}
+	key, _ := ctx.Value(apiKeyKey{}).(string)
+	return key
+}
+
+// authenticate sets the API key for req, using the fallback key once the
+// primary has been rejected, or a bearer token with WithTokenSource. An
+// override replaces all of them.
+func (c *Client) authenticate(ctx context.Context, req *http.Request, override string) error {
+	if override != "" {
+		req.Header.Set(apiKeyHeader, override)
+		return nil
+	}
+	if c.tokenSource != nil {
+		return c.setBearerToken(req)
+	}
+	key, err := c.credentials.APIKey(ctx)
+	if fallback := c.fallback.Load(); fallback != nil {
+		if err != nil || !fallback.over(key, c.clock.Now()) {
+			req.Header.Set(apiKeyHeader, c.fallbackAPIKey)
+			return nil
+		}
+		c.fallback.CompareAndSwap(fallback, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if last := c.lastSecret.Load(); last == nil || *last != key {
+		stored := key
+		c.lastSecret.Store(&stored)
+	}
+	req.Header.Set(apiKeyHeader, key)
+	return nil
+}
+
+// fallbackRetryInterval is how long the fallback key is used before the
+// primary key is tried again, when its provider still returns the
+// rejected key.
+const fallbackRetryInterval = 5 * time.Minute
+
+// fallbackState records that the client switched to the fallback key after
+// the primary key rejected was refused at at.
+type fallbackState struct {
+	rejected string
+	at       time.Time
+}
+
+// over reports whether the primary key should be used again: its provider
+// now returns a different key, or the retry interval has passed.
+func (f *fallbackState) over(primary string, now time.Time) bool {
+	return primary != f.rejected || now.Sub(f.at) >= fallbackRetryInterval
+}
+
+// reauthenticate is called on a 401. It drops any cached key and reports
+// the provider's current key if it differs from the rejected one.
+func (c *Client) reauthenticate(ctx context.Context, rejected string) (string, bool) {
+	inv, ok := c.credentials.(invalidator)
+	if !ok || c.tokenSource != nil || c.fallback.Load() != nil {
+		return "", false
+	}
+	inv.Invalidate()
+	key, err := c.credentials.APIKey(ctx)
+	if err != nil || key == rejected {
+		return "", false
+	}
+	c.lastSecret.Store(&key)
+	return key, true
+}
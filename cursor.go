@@ -0,0 +1,123 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// cursorVersion is bumped if the encoding of Cursor changes.
+const cursorVersion = 1
+
+var ErrInvalidCursor = errors.New("jwt-revoke: invalid cursor")
+
+// Cursor is a resumable position in a listing: the filters it was started
+// with and where the next page begins. Its String form can be stored, e.g.
+// to checkpoint a long export, and restored with ParseCursor after a
+// restart.
+type Cursor struct {
+	params ListOptions
+	done   bool
+}
+
+// NewCursor returns a cursor at the start of the listing params selects. A
+// non-empty params.Cursor starts from that page instead.
+func NewCursor(params ListOptions) Cursor {
+	return Cursor{params: params}
+}
+
+// Options returns the ListOptions for fetching the page the cursor points
+// at.
+func (c Cursor) Options() ListOptions {
+	return c.params
+}
+
+// Done reports whether the listing has been read to the end.
+func (c Cursor) Done() bool {
+	return c.done
+}
+
+// encodedCursor stores the listing as the query string the API receives.
+type encodedCursor struct {
+	Version int    `json:"v"`
+	Query   string `json:"q"`
+	Done    bool   `json:"d,omitempty"`
+}
+
+func (c Cursor) String() string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(encodedCursor{Version: cursorVersion, Query: c.params.values().Encode(), Done: c.done})
+	return base64.RawURLEncoding.EncodeToString(bytes.TrimSpace(buf.Bytes()))
+}
+
+func ParseCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var enc encodedCursor
+	if err := json.Unmarshal(data, &enc); err != nil || enc.Version != cursorVersion {
+		return Cursor{}, ErrInvalidCursor
+	}
+	query, err := url.ParseQuery(enc.Query)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	params, err := listOptionsFromValues(query)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{params: params, done: enc.Done}, nil
+}
+
+// listOptionsFromValues reverses ListOptions.values.
+func listOptionsFromValues(v url.Values) (ListOptions, error) {
+	o := ListOptions{
+		Status:         RevocationStatus(v.Get("status")),
+		Reason:         v.Get("reason"),
+		RevokedByEmail: v.Get("revoked_by_email"),
+		FamilyID:       v.Get("family_id"),
+		KeyID:          v.Get("key_id"),
+		SortBy:         SortField(v.Get("sort_by")),
+		SortOrder:      SortOrder(v.Get("sort_order")),
+		Cursor:         v.Get("cursor"),
+	}
+	for key, t := range map[string]*time.Time{
+		"revoked_after":  &o.RevokedAfter,
+		"revoked_before": &o.RevokedBefore,
+		"expires_after":  &o.ExpiresAfter,
+		"expires_before": &o.ExpiresBefore,
+	} {
+		if raw := v.Get(key); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return ListOptions{}, err
+			}
+			*t = parsed
+		}
+	}
+	for _, label := range v["label"] {
+		key, value, err := ParseLabel(label)
+		if err != nil {
+			return ListOptions{}, err
+		}
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+		o.Labels[key] = value
+	}
+	if raw := v.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return ListOptions{}, err
+		}
+		o.Limit = limit
+	}
+	return o, nil
+}
@@ -0,0 +1,60 @@
+package jwtrevokeapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var sensitiveHeaderPattern = regexp.MustCompile(`(?im)^(X-Api-Key|Authorization|Cookie|Set-Cookie):.*$`)
+
+func WithDebug(w io.Writer) ClientOption {
+	return func(c *Client) {
+		c.debugWriter = w
+	}
+}
+
+type debugTransport struct {
+	next   http.RoundTripper
+	w      io.Writer
+	mu     sync.Mutex
+	client *Client
+}
+
+func transportOrDefault(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		return http.DefaultTransport
+	}
+	return rt
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+		t.write("request", dump)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.write(fmt.Sprintf("error after %s", time.Since(start)), []byte(err.Error()))
+		return nil, err
+	}
+
+	if dump, err := httputil.DumpResponse(resp, true); err == nil {
+		t.write(fmt.Sprintf("response after %s", time.Since(start)), dump)
+	}
+	return resp, nil
+}
+
+func (t *debugTransport) write(label string, dump []byte) {
+	masked := sensitiveHeaderPattern.ReplaceAllString(string(dump), "$1: "+redacted)
+	masked = t.client.redactString(masked)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, "---- jwtrevoke %s ----\n%s\n", label, masked)
+}
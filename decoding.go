@@ -0,0 +1,120 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// WithStrictDecoding rejects responses containing fields the SDK does not
+// know about, surfacing schema drift early, e.g. in CI against the sandbox.
+// By default unknown fields are ignored so new server fields don't break
+// older clients. Error responses are always decoded leniently.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// Codec is a JSON implementation, e.g. a thin wrapper around sonic or
+// go-json. It must behave like encoding/json, honoring struct tags and the
+// json.Marshaler and json.Unmarshaler methods of the SDK's types.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// WithJSONCodec swaps encoding/json for codec when decoding API responses,
+// list pages included, and encoding bulk request bodies such as batch
+// revocations and imports. Responses are still decoded by encoding/json
+// under WithStrictDecoding, since a Codec cannot reject unknown fields.
+func WithJSONCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// responseCodec is the Codec responses are decoded with, or nil for
+// encoding/json.
+func (c *Client) responseCodec() Codec {
+	if c.strictDecoding {
+		return nil
+	}
+	return c.codec
+}
+
+// decodeJSON decodes the JSON document in r into v.
+func (c *Client) decodeJSON(r io.Reader, v any) error {
+	codec := c.responseCodec()
+	if codec == nil {
+		return c.newDecoder(r).Decode(v)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, v)
+}
+
+func (c *Client) marshalJSON(v any) ([]byte, error) {
+	if c.codec == nil {
+		return json.Marshal(v)
+	}
+	return c.codec.Marshal(v)
+}
+
+func (c *Client) newDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	if c.strictDecoding {
+		dec.DisallowUnknownFields()
+	}
+	return dec
+}
+
+// call sends req and decodes a successful response into a T. Most endpoints
+// return their result in an envelope under a single key, e.g.
+// {"token": {...}}; an empty key decodes the whole body, e.g. a page. Errors,
+// retries, and WithResponseCapture are handled by doRequest, so endpoints
+// built on call behave alike.
+func call[T any](ctx context.Context, c *Client, req *http.Request, key string, opts ...CallOption) (*T, error) {
+	resp, err := c.doRequest(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var v T
+	if key == "" {
+		err = c.decodeJSON(resp.Body, &v)
+	} else {
+		err = c.decodeEnvelope(resp.Body, key, &v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// decodeEnvelope decodes the value under key into v. A missing key leaves v
+// unchanged; with WithStrictDecoding, other keys are rejected.
+func (c *Client) decodeEnvelope(r io.Reader, key string, v any) error {
+	var envelope map[string]json.RawMessage
+	if err := c.decodeJSON(r, &envelope); err != nil {
+		return err
+	}
+	if c.strictDecoding {
+		for k := range envelope {
+			if k != key {
+				return fmt.Errorf("json: unknown field %q", k)
+			}
+		}
+	}
+	raw, ok := envelope[key]
+	if !ok {
+		return nil
+	}
+	return c.decodeJSON(bytes.NewReader(raw), v)
+}
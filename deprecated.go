@@ -0,0 +1,123 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// The methods below predate the Revocations sub-client and are kept so
+// existing code keeps compiling.
+
+// Deprecated: Use RevokeSession instead.
+func (s *RevocationsService) RevokeBySession(ctx context.Context, sid string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	return s.RevokeSession(ctx, sid, reason, opts...)
+}
+
+// Deprecated: Use Revocations.List instead.
+func (c *Client) ListRevokedTokens(opts ...CallOption) ([]RevokedToken, error) {
+	return c.Revocations.List(context.Background(), ListOptions{}, opts...)
+}
+
+// RevokeToken revokes jwtID until expiryDate. A zero expiryDate makes the
+// revocation permanent.
+//
+// Deprecated: Use Revocations.Revoke with NewRevokeRequest instead.
+func (c *Client) RevokeToken(jwtID string, reason ReasonCode, expiryDate time.Time, opts ...CallOption) (*RevokedToken, error) {
+	return c.Revocations.Revoke(context.Background(), NewRevokeRequest(jwtID, reason, expiryDate), opts...)
+}
+
+// Deprecated: Use Revocations.Delete instead.
+func (c *Client) DeleteRevokedToken(jwtID string, opts ...CallOption) error {
+	return c.Revocations.Delete(context.Background(), jwtID, opts...)
+}
+
+// Deprecated: Use Revocations.List instead.
+func (c *Client) ListRevokedTokensWithOptions(ctx context.Context, params ListOptions, opts ...CallOption) ([]RevokedToken, error) {
+	return c.Revocations.List(ctx, params, opts...)
+}
+
+// Deprecated: Use Revocations.ListPage instead.
+func (c *Client) ListRevokedTokensPage(ctx context.Context, params ListOptions, opts ...CallOption) (*RevocationPage, error) {
+	return c.Revocations.ListPage(ctx, params, opts...)
+}
+
+// Deprecated: Use Revocations.Get instead.
+func (c *Client) GetRevokedToken(ctx context.Context, jwtID string, opts ...CallOption) (*RevokedToken, error) {
+	return c.Revocations.Get(ctx, jwtID, opts...)
+}
+
+// Deprecated: Use Revocations.IsRevoked instead.
+func (c *Client) IsRevoked(ctx context.Context, jwtID string, opts ...CallOption) (bool, error) {
+	return c.Revocations.IsRevoked(ctx, jwtID, opts...)
+}
+
+// Deprecated: Use Revocations.Revoke instead.
+func (c *Client) Revoke(ctx context.Context, payload RevokeRequest, opts ...CallOption) (*RevokedToken, error) {
+	return c.Revocations.Revoke(ctx, payload, opts...)
+}
+
+// Deprecated: Use Revocations.Update instead.
+func (c *Client) UpdateRevokedToken(ctx context.Context, jwtID string, update UpdateRequest, opts ...CallOption) (*RevokedToken, error) {
+	return c.Revocations.Update(ctx, jwtID, update, opts...)
+}
+
+// Deprecated: Use Revocations.DeleteMany instead.
+func (c *Client) DeleteRevokedTokens(ctx context.Context, jwtIDs []string, opts ...CallOption) error {
+	return c.Revocations.DeleteMany(ctx, jwtIDs, opts...)
+}
+
+// Deprecated: Use Revocations.RevokeBatch instead.
+func (c *Client) RevokeBatch(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) ([]RevokedToken, error) {
+	return c.Revocations.RevokeBatch(ctx, revocations, opts...)
+}
+
+// Deprecated: Use Revocations.RevokeBySubject instead.
+func (c *Client) RevokeBySubject(ctx context.Context, sub string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	return c.Revocations.RevokeBySubject(ctx, sub, reason, opts...)
+}
+
+// Deprecated: Use Revocations.RevokeByIssuer instead.
+func (c *Client) RevokeByIssuer(ctx context.Context, issuer string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	return c.Revocations.RevokeByIssuer(ctx, issuer, reason, opts...)
+}
+
+// Deprecated: Use Revocations.RevokeByAudience instead.
+func (c *Client) RevokeByAudience(ctx context.Context, audience string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	return c.Revocations.RevokeByAudience(ctx, audience, reason, opts...)
+}
+
+// Deprecated: Use Revocations.RevokeAll instead.
+func (c *Client) RevokeAll(ctx context.Context, params RevokeAllOptions, opts ...CallOption) (*ScopedRevocationResult, error) {
+	return c.Revocations.RevokeAll(ctx, params, opts...)
+}
+
+// Deprecated: Use Revocations.Stream instead.
+func (c *Client) StreamRevokedTokens(ctx context.Context, params ListOptions, fn func(RevokedToken) error, opts ...CallOption) error {
+	return c.Revocations.Stream(ctx, params, fn, opts...)
+}
+
+// Deprecated: Use Revocations.Changes instead.
+func (c *Client) ListChanges(ctx context.Context, since time.Time, opts ...CallOption) (*ChangeSet, error) {
+	return c.Revocations.Changes(ctx, since, opts...)
+}
+
+// Deprecated: Use Revocations.Export instead.
+func (c *Client) Export(ctx context.Context, w io.Writer, format ExportFormat, opts ...CallOption) error {
+	return c.Revocations.Export(ctx, w, format, opts...)
+}
+
+// Deprecated: Use Revocations.Import instead.
+func (c *Client) Import(ctx context.Context, r io.Reader, opts ImportOptions, callOpts ...CallOption) (*ImportReport, error) {
+	return c.Revocations.Import(ctx, r, opts, callOpts...)
+}
+
+// Deprecated: Use Revocations.Stats instead.
+func (c *Client) Stats(ctx context.Context, opts ...CallOption) (*RevocationStats, error) {
+	return c.Revocations.Stats(ctx, opts...)
+}
+
+// Deprecated: Use Revocations.Analytics instead.
+func (c *Client) Analytics(ctx context.Context, query AnalyticsQuery, opts ...CallOption) ([]AnalyticsBucket, error) {
+	return c.Revocations.Analytics(ctx, query, opts...)
+}
@@ -0,0 +1,126 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationNotice describes an endpoint the API has marked for removal
+// with Deprecation or Sunset response headers.
+type DeprecationNotice struct {
+	Method string
+	// Endpoint is the request path with IDs replaced by {id}, so every call
+	// to an endpoint shares one notice.
+	Endpoint string
+	// Deprecated is when the endpoint was or will be deprecated; zero when
+	// the API only said that it is.
+	Deprecated time.Time
+	// Sunset is when the endpoint stops working; zero if not announced.
+	Sunset time.Time
+	// Link is the migration guide linked with rel="deprecation" or
+	// rel="sunset", if any.
+	Link string
+}
+
+// WithOnDeprecation calls fn the first time each endpoint responds with a
+// Deprecation or Sunset header, e.g. to feed telemetry that flags upcoming
+// API removals. Clients derived with With share what has been reported. The
+// notice is also logged at Warn, with or without fn. fn runs on the
+// request's goroutine and should return quickly.
+func WithOnDeprecation(fn func(ctx context.Context, notice DeprecationNotice)) ClientOption {
+	return func(c *Client) {
+		c.onDeprecation = fn
+	}
+}
+
+// observeDeprecation reports the Deprecation and Sunset headers of resp
+// once per endpoint.
+func (c *Client) observeDeprecation(ctx context.Context, req *http.Request, resp *http.Response) {
+	deprecation, sunset := resp.Header.Get("Deprecation"), resp.Header.Get("Sunset")
+	if deprecation == "" && sunset == "" {
+		return
+	}
+	notice := DeprecationNotice{Method: req.Method, Endpoint: endpointPattern(req.URL.Path)}
+	if _, seen := c.deprecations.LoadOrStore(notice.Method+" "+notice.Endpoint, struct{}{}); seen {
+		return
+	}
+	notice.Deprecated = parseDeprecationDate(deprecation)
+	if at, err := http.ParseTime(sunset); err == nil {
+		notice.Sunset = at
+	}
+	notice.Link = deprecationLink(resp.Header["Link"])
+
+	args := []any{"method", notice.Method, "endpoint", notice.Endpoint}
+	if !notice.Deprecated.IsZero() {
+		args = append(args, "deprecated", notice.Deprecated)
+	}
+	if !notice.Sunset.IsZero() {
+		args = append(args, "sunset", notice.Sunset)
+	}
+	if notice.Link != "" {
+		args = append(args, "link", notice.Link)
+	}
+	c.log(ctx, slog.LevelWarn, "jwtrevoke: endpoint is deprecated", args...)
+	if c.onDeprecation != nil {
+		c.onDeprecation(ctx, notice)
+	}
+}
+
+// parseDeprecationDate reads a Deprecation header, either an RFC 9745 date
+// such as @1688169599 or, from earlier drafts, an HTTP date or "true".
+func parseDeprecationDate(v string) time.Time {
+	if seconds, ok := strings.CutPrefix(v, "@"); ok {
+		if n, err := strconv.ParseInt(seconds, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return at
+	}
+	return time.Time{}
+}
+
+// deprecationLink returns the target of the first Link with
+// rel="deprecation", falling back to rel="sunset".
+func deprecationLink(values []string) string {
+	var sunset string
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, _ := strings.Cut(link, ";")
+			target = strings.Trim(strings.TrimSpace(target), "<>")
+			for _, param := range strings.Split(params, ";") {
+				name, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(rel, `"`)) {
+					switch strings.ToLower(rel) {
+					case "deprecation":
+						return target
+					case "sunset":
+						if sunset == "" {
+							sunset = target
+						}
+					}
+				}
+			}
+		}
+	}
+	return sunset
+}
+
+// endpointPattern replaces the path segments that look like IDs, i.e. ones
+// that are not plain lowercase words, with {id}.
+func endpointPattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.Trim(s, "abcdefghijklmnopqrstuvwxyz-") != "" {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
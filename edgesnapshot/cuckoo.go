@@ -0,0 +1,132 @@
+package edgesnapshot
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+)
+
+const (
+	bucketSize = 4
+	maxKicks   = 500
+	// fingerprintMix spreads a fingerprint over the bucket index space for
+	// the alternate bucket, as in the MurmurHash2 mixing step.
+	fingerprintMix = 0x5bd1e995
+)
+
+var ErrFilterFull = errors.New("jwt-revoke: cuckoo filter is full, rebuild the snapshot")
+
+// cuckooFilter has 16-bit fingerprints in buckets of four. A jti maps to
+// bucket i1 from the first 4 bytes of its SHA-256 and has the fingerprint
+// from the next 2 bytes (0 is stored as 1, since 0 marks an empty slot). Its
+// alternate bucket is i1 XOR (fingerprint × 0x5bd1e995), modulo the number
+// of buckets.
+type cuckooFilter struct {
+	buckets []uint16
+	mask    uint32
+	count   int
+}
+
+func newCuckooFilter(capacity int) *cuckooFilter {
+	n := uint32(1)
+	for int(n)*bucketSize*9/10 < capacity {
+		n <<= 1
+	}
+	return &cuckooFilter{buckets: make([]uint16, int(n)*bucketSize), mask: n - 1}
+}
+
+func (f *cuckooFilter) locate(jwtID string) (uint32, uint32, uint16) {
+	sum := sha256.Sum256([]byte(jwtID))
+	i1 := binary.BigEndian.Uint32(sum[:4]) & f.mask
+	fp := binary.BigEndian.Uint16(sum[4:6])
+	if fp == 0 {
+		fp = 1
+	}
+	return i1, f.alt(i1, fp), fp
+}
+
+func (f *cuckooFilter) alt(i uint32, fp uint16) uint32 {
+	return (i ^ uint32(fp)*fingerprintMix) & f.mask
+}
+
+func (f *cuckooFilter) bucket(i uint32) []uint16 {
+	return f.buckets[i*bucketSize : (i+1)*bucketSize]
+}
+
+func (f *cuckooFilter) contains(jwtID string) bool {
+	i1, i2, fp := f.locate(jwtID)
+	for _, slot := range f.bucket(i1) {
+		if slot == fp {
+			return true
+		}
+	}
+	for _, slot := range f.bucket(i2) {
+		if slot == fp {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *cuckooFilter) insert(jwtID string) error {
+	i1, i2, fp := f.locate(jwtID)
+	if f.place(i1, fp) || f.place(i2, fp) {
+		f.count++
+		return nil
+	}
+
+	// Evict fingerprints until one finds a free slot. The filter is restored
+	// when that fails, so a full filter keeps its contents.
+	type move struct {
+		bucket uint32
+		slot   int
+		fp     uint16
+	}
+	var moves []move
+	i := i1
+	if rand.Intn(2) == 1 {
+		i = i2
+	}
+	for k := 0; k < maxKicks; k++ {
+		slot := rand.Intn(bucketSize)
+		b := f.bucket(i)
+		moves = append(moves, move{i, slot, b[slot]})
+		fp, b[slot] = b[slot], fp
+		i = f.alt(i, fp)
+		if f.place(i, fp) {
+			f.count++
+			return nil
+		}
+	}
+	for j := len(moves) - 1; j >= 0; j-- {
+		f.bucket(moves[j].bucket)[moves[j].slot] = moves[j].fp
+	}
+	return ErrFilterFull
+}
+
+func (f *cuckooFilter) place(i uint32, fp uint16) bool {
+	b := f.bucket(i)
+	for j, slot := range b {
+		if slot == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *cuckooFilter) remove(jwtID string) bool {
+	i1, i2, fp := f.locate(jwtID)
+	for _, i := range []uint32{i1, i2} {
+		b := f.bucket(i)
+		for j, slot := range b {
+			if slot == fp {
+				b[j] = 0
+				f.count--
+				return true
+			}
+		}
+	}
+	return false
+}
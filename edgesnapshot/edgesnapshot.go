@@ -0,0 +1,326 @@
+// Package edgesnapshot renders the revocation set into a compact binary blob
+// for edge runtimes such as Cloudflare Workers or Fastly, which load it from
+// KV storage and check tokens without calling the API. The reader in this
+// package is the reference implementation edge code can be tested against.
+//
+// Every snapshot starts with this header, with all integers big-endian:
+//
+//	magic        4 bytes  "JRES"
+//	format       uint8    1 (sorted hashes) or 2 (cuckoo filter)
+//	entry size   uint8    8 for sorted hashes, 2 for cuckoo fingerprints
+//	reserved     2 bytes  zero
+//	generated at int64    Unix seconds
+//	count        uint32   number of hashes, or of cuckoo buckets
+//
+// and ends with a CRC-32 (IEEE) of all preceding bytes as a uint32.
+//
+// In the sorted hashes format the header is followed by count hashes, sorted
+// ascending without duplicates. Each is the first 8 bytes of SHA-256 over a
+// jti, and a lookup binary searches them.
+//
+// In the cuckoo filter format the header is followed by the number of
+// stored jtis as a uint32 and then count buckets of four 16-bit
+// fingerprints, where 0 marks an empty slot; see cuckoo.go for the hashing.
+// A cuckoo filter is a third to half the size, answers a false positive for
+// roughly one in 8,000 unknown jtis, and can be updated in place with
+// Add and Delete, so a mirrored set can apply un-revocations without a full
+// rebuild. Delete only removes jtis the Set knows it holds, since the
+// fingerprint of an unknown jti may match a stored one.
+package edgesnapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+// Format selects the snapshot encoding.
+type Format uint8
+
+const (
+	FormatSortedHashes Format = 1
+	FormatCuckoo       Format = 2
+)
+
+const (
+	HashSize        = 8
+	fingerprintSize = 2
+	headerSize      = 20
+)
+
+var magic = []byte("JRES")
+
+var (
+	ErrInvalidSnapshot = errors.New("jwt-revoke: invalid edge snapshot")
+	ErrReadOnly        = errors.New("jwt-revoke: sorted hash snapshots cannot be updated, use FormatCuckoo")
+	ErrUntracked       = errors.New("jwt-revoke: jti is not known to be in the snapshot, Track its jtis or rebuild it")
+)
+
+type Options struct {
+	// Format defaults to FormatSortedHashes.
+	Format Format
+}
+
+// Export writes a sorted hashes snapshot of every revocation that has not
+// expired, including scheduled ones, so the snapshot still holds them once
+// they take effect. Checks against it must confirm hits with the API, as
+// HybridPrefilter does, since a scheduled revocation is not in effect yet.
+func Export(ctx context.Context, client *jwtrevokeapi.Client, w io.Writer) error {
+	return ExportWithOptions(ctx, client, w, Options{})
+}
+
+func ExportWithOptions(ctx context.Context, client *jwtrevokeapi.Client, w io.Writer, opts Options) error {
+	now := time.Now()
+	var jwtIDs []string
+	err := client.Revocations.Stream(ctx, jwtrevokeapi.ListOptions{}, func(t jwtrevokeapi.RevokedToken) error {
+		if t.ExpiryDate != nil && !t.ExpiryDate.After(now) {
+			return nil
+		}
+		jwtIDs = append(jwtIDs, t.JwtID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	set, err := Build(jwtIDs, now, opts)
+	if err != nil {
+		return err
+	}
+	_, err = set.WriteTo(w)
+	return err
+}
+
+// Encode writes a sorted hashes snapshot of jwtIDs.
+func Encode(w io.Writer, jwtIDs []string, generatedAt time.Time) error {
+	set, err := Build(jwtIDs, generatedAt, Options{})
+	if err != nil {
+		return err
+	}
+	_, err = set.WriteTo(w)
+	return err
+}
+
+// Hash returns the sorted hashes snapshot hash of jwtID.
+func Hash(jwtID string) [HashSize]byte {
+	sum := sha256.Sum256([]byte(jwtID))
+	return [HashSize]byte(sum[:HashSize])
+}
+
+// Set is a snapshot in memory. It is not safe for concurrent updates.
+type Set struct {
+	format      Format
+	generatedAt time.Time
+	hashes      []byte
+	filter      *cuckooFilter
+	// members are the jtis known to be in a cuckoo filter. A fingerprint
+	// is only removed for one of them, since an unknown jti may collide with
+	// a stored one. tracked is false for a decoded filter until Track.
+	members map[string]struct{}
+	tracked bool
+}
+
+// Build creates a snapshot of jwtIDs.
+func Build(jwtIDs []string, generatedAt time.Time, opts Options) (*Set, error) {
+	set := &Set{format: opts.Format, generatedAt: generatedAt}
+	switch opts.Format {
+	case 0, FormatSortedHashes:
+		set.format = FormatSortedHashes
+		hashes := make([][HashSize]byte, 0, len(jwtIDs))
+		for _, jwtID := range jwtIDs {
+			hashes = append(hashes, Hash(jwtID))
+		}
+		slices.SortFunc(hashes, func(a, b [HashSize]byte) int { return bytes.Compare(a[:], b[:]) })
+		for _, h := range slices.Compact(hashes) {
+			set.hashes = append(set.hashes, h[:]...)
+		}
+	case FormatCuckoo:
+		unique := slices.Clone(jwtIDs)
+		slices.Sort(unique)
+		unique = slices.Compact(unique)
+		set.filter = newCuckooFilter(len(unique))
+		set.members = make(map[string]struct{}, len(unique))
+		set.tracked = true
+		for _, jwtID := range unique {
+			if err := set.filter.insert(jwtID); err != nil {
+				return nil, err
+			}
+			set.members[jwtID] = struct{}{}
+		}
+	default:
+		return nil, fmt.Errorf("jwt-revoke: unknown edge snapshot format %d", opts.Format)
+	}
+	return set, nil
+}
+
+// Read decodes a snapshot from r.
+func Read(r io.Reader) (*Set, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Decode checks and decodes a snapshot in either format. A sorted hashes
+// Set keeps a reference to data.
+func Decode(data []byte) (*Set, error) {
+	if len(data) < headerSize+4 || !bytes.Equal(data[:4], magic) {
+		return nil, ErrInvalidSnapshot
+	}
+	format, entrySize := Format(data[4]), int(data[5])
+	count := int(binary.BigEndian.Uint32(data[16:]))
+	var want int
+	switch {
+	case format == FormatSortedHashes && entrySize == HashSize:
+		want = headerSize + count*HashSize + 4
+	case format == FormatCuckoo && entrySize == fingerprintSize && count > 0 && count&(count-1) == 0:
+		want = headerSize + 4 + count*bucketSize*fingerprintSize + 4
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %d with entry size %d", ErrInvalidSnapshot, format, entrySize)
+	}
+	if len(data) != want {
+		return nil, fmt.Errorf("%w: length does not match %d entries", ErrInvalidSnapshot, count)
+	}
+	body, checksum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshot)
+	}
+
+	set := &Set{format: format, generatedAt: time.Unix(int64(binary.BigEndian.Uint64(data[8:])), 0)}
+	if format == FormatSortedHashes {
+		set.hashes = body[headerSize:]
+		return set, nil
+	}
+	set.filter = &cuckooFilter{
+		buckets: make([]uint16, count*bucketSize),
+		mask:    uint32(count - 1),
+		count:   int(binary.BigEndian.Uint32(body[headerSize:])),
+	}
+	set.members = make(map[string]struct{})
+	slots := body[headerSize+4:]
+	for i := range set.filter.buckets {
+		set.filter.buckets[i] = binary.BigEndian.Uint16(slots[i*fingerprintSize:])
+	}
+	return set, nil
+}
+
+// WriteTo encodes the snapshot, including updates made with Add and Delete.
+func (s *Set) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, headerSize, headerSize+len(s.hashes)+4)
+	copy(buf, magic)
+	buf[4] = byte(s.format)
+	binary.BigEndian.PutUint64(buf[8:], uint64(s.generatedAt.Unix()))
+	if s.filter == nil {
+		buf[5] = HashSize
+		binary.BigEndian.PutUint32(buf[16:], uint32(s.Len()))
+		buf = append(buf, s.hashes...)
+	} else {
+		buf[5] = fingerprintSize
+		binary.BigEndian.PutUint32(buf[16:], s.filter.mask+1)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(s.filter.count))
+		for _, fp := range s.filter.buckets {
+			buf = binary.BigEndian.AppendUint16(buf, fp)
+		}
+	}
+	buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf))
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// Contains reports whether jwtID is in the snapshot.
+func (s *Set) Contains(jwtID string) bool {
+	if s.filter != nil {
+		return s.filter.contains(jwtID)
+	}
+	h := Hash(jwtID)
+	lo, hi := 0, s.Len()
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		switch bytes.Compare(s.hashes[mid*HashSize:(mid+1)*HashSize], h[:]) {
+		case 0:
+			return true
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return false
+}
+
+// Add inserts jwtID into a cuckoo filter snapshot, e.g. when a revoked
+// event arrives. Adding a jti the set already holds does nothing. When the
+// filter is too full, ErrFilterFull is returned and the set is unchanged.
+func (s *Set) Add(jwtID string) error {
+	if s.filter == nil {
+		return ErrReadOnly
+	}
+	if _, ok := s.members[jwtID]; ok {
+		return nil
+	}
+	if err := s.filter.insert(jwtID); err != nil {
+		return err
+	}
+	s.members[jwtID] = struct{}{}
+	return nil
+}
+
+// Delete removes jwtID from a cuckoo filter snapshot, e.g. when a deleted
+// event arrives, and reports whether it was removed. Only jtis the set
+// knows it holds, from Build, Add or Track, are removed; any other jti
+// could share a fingerprint with a stored one. A decoded set that has not
+// been tracked returns ErrUntracked for jtis it did not add.
+func (s *Set) Delete(jwtID string) (bool, error) {
+	if s.filter == nil {
+		return false, ErrReadOnly
+	}
+	if _, ok := s.members[jwtID]; !ok {
+		if !s.tracked {
+			return false, ErrUntracked
+		}
+		return false, nil
+	}
+	delete(s.members, jwtID)
+	return s.filter.remove(jwtID), nil
+}
+
+// Track records that a decoded cuckoo filter snapshot holds jwtIDs, e.g.
+// the revocations it was built from, so that Delete can remove them. jtis
+// the filter does not contain are skipped.
+func (s *Set) Track(jwtIDs []string) error {
+	if s.filter == nil {
+		return ErrReadOnly
+	}
+	for _, jwtID := range jwtIDs {
+		if s.filter.contains(jwtID) {
+			s.members[jwtID] = struct{}{}
+		}
+	}
+	s.tracked = true
+	return nil
+}
+
+// Len returns the number of jtis in the snapshot.
+func (s *Set) Len() int {
+	if s.filter != nil {
+		return s.filter.count
+	}
+	return len(s.hashes) / HashSize
+}
+
+func (s *Set) Format() Format {
+	return s.format
+}
+
+func (s *Set) GeneratedAt() time.Time {
+	return s.generatedAt
+}
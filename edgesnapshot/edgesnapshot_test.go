@@ -0,0 +1,137 @@
+package edgesnapshot_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"testing"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/jwtrevoke/go-sdk/edgesnapshot"
+	"github.com/jwtrevoke/go-sdk/jwtrevoketest"
+)
+
+// TestExportReaderParity exports the revocations of a fake API and checks
+// that the reader answers every jti the same way the API does.
+func TestExportReaderParity(t *testing.T) {
+	srv := jwtrevoketest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	var revoked []string
+	for i := 0; i < 500; i++ {
+		jwtID := fmt.Sprintf("token-%d", i)
+		srv.Seed(jwtrevokeapi.NewRevokeRequest(jwtID, jwtrevokeapi.ReasonLogout, time.Now().Add(time.Hour)))
+		revoked = append(revoked, jwtID)
+	}
+	srv.Seed(jwtrevokeapi.NewRevokeRequest("expired", jwtrevokeapi.ReasonLogout, time.Now().Add(-time.Hour)))
+
+	for _, format := range []edgesnapshot.Format{edgesnapshot.FormatSortedHashes, edgesnapshot.FormatCuckoo} {
+		t.Run(fmt.Sprintf("format %d", format), func(t *testing.T) {
+			ctx := context.Background()
+			var buf bytes.Buffer
+			if err := edgesnapshot.ExportWithOptions(ctx, client, &buf, edgesnapshot.Options{Format: format}); err != nil {
+				t.Fatalf("ExportWithOptions: %v", err)
+			}
+			set, err := edgesnapshot.Read(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if set.Format() != format || set.Len() != len(revoked) {
+				t.Fatalf("Read = format %d with %d jtis, want format %d with %d", set.Format(), set.Len(), format, len(revoked))
+			}
+
+			for _, jwtID := range revoked {
+				want, err := client.Revocations.IsRevoked(ctx, jwtID)
+				if err != nil {
+					t.Fatalf("IsRevoked(%s): %v", jwtID, err)
+				}
+				if got := set.Contains(jwtID); got != want {
+					t.Errorf("Contains(%s) = %v, API says %v", jwtID, got, want)
+				}
+			}
+			if format == edgesnapshot.FormatSortedHashes && set.Contains("expired") {
+				t.Error("snapshot holds an expired revocation")
+			}
+
+			// Sorted hashes have no false positives in practice; the cuckoo
+			// filter allows about one in 8,000.
+			var falsePositives int
+			for i := 0; i < 10000; i++ {
+				if set.Contains(fmt.Sprintf("unknown-%d", i)) {
+					falsePositives++
+				}
+			}
+			limit := 0
+			if format == edgesnapshot.FormatCuckoo {
+				limit = 10
+			}
+			if falsePositives > limit {
+				t.Errorf("%d false positives in 10000 unknown jtis, want at most %d", falsePositives, limit)
+			}
+
+			// A decoded snapshot encodes back to the same bytes.
+			var again bytes.Buffer
+			if _, err := set.WriteTo(&again); err != nil {
+				t.Fatalf("WriteTo: %v", err)
+			}
+			if !bytes.Equal(again.Bytes(), buf.Bytes()) {
+				t.Error("re-encoding a decoded snapshot changed its bytes")
+			}
+		})
+	}
+}
+
+// TestSortedHashesLayout decodes a snapshot by hand, following the layout
+// in the package documentation, as an edge reader in another language
+// would.
+func TestSortedHashesLayout(t *testing.T) {
+	generatedAt := time.Unix(1700000000, 0)
+	jwtIDs := []string{"b", "a", "c", "a"}
+	var buf bytes.Buffer
+	if err := edgesnapshot.Encode(&buf, jwtIDs, generatedAt); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	data := buf.Bytes()
+
+	if got := string(data[:4]); got != "JRES" {
+		t.Fatalf("magic = %q", got)
+	}
+	if data[4] != byte(edgesnapshot.FormatSortedHashes) || data[5] != edgesnapshot.HashSize {
+		t.Fatalf("format, entry size = %d, %d", data[4], data[5])
+	}
+	if got := int64(binary.BigEndian.Uint64(data[8:])); got != generatedAt.Unix() {
+		t.Errorf("generated at = %d, want %d", got, generatedAt.Unix())
+	}
+	count := int(binary.BigEndian.Uint32(data[16:]))
+	if count != 3 {
+		t.Fatalf("count = %d, want 3 distinct jtis", count)
+	}
+	if len(data) != 20+count*edgesnapshot.HashSize+4 {
+		t.Fatalf("length = %d", len(data))
+	}
+	body := data[:len(data)-4]
+	if binary.BigEndian.Uint32(data[len(data)-4:]) != crc32.ChecksumIEEE(body) {
+		t.Error("checksum does not cover the preceding bytes")
+	}
+
+	hashes := body[20:]
+	for i := 1; i < count; i++ {
+		if bytes.Compare(hashes[(i-1)*8:i*8], hashes[i*8:(i+1)*8]) >= 0 {
+			t.Fatal("hashes are not sorted ascending without duplicates")
+		}
+	}
+	for _, jwtID := range []string{"a", "b", "c"} {
+		h := edgesnapshot.Hash(jwtID)
+		found := false
+		for i := 0; i < count; i++ {
+			found = found || bytes.Equal(hashes[i*8:(i+1)*8], h[:])
+		}
+		if !found {
+			t.Errorf("hash of %q missing", jwtID)
+		}
+	}
+}
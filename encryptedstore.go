@@ -0,0 +1,151 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrUnknownKey = errors.New("jwt-revoke: value is encrypted with an unknown key")
+
+// Keyring supplies the AES keys of an encrypted store: 16, 24 or 32 bytes
+// for AES-128, AES-192 or AES-256. It is consulted on every read and write,
+// so keyrings backed by a KMS should cache keys.
+type Keyring interface {
+	// CurrentKey returns the key new values are encrypted with, and an ID
+	// of at most 255 bytes that is stored alongside them.
+	CurrentKey(ctx context.Context) (id string, key []byte, err error)
+	// Key returns the key with the given ID, so values written before a
+	// rotation stay readable. Unknown IDs return ErrUnknownKey.
+	Key(ctx context.Context, id string) ([]byte, error)
+}
+
+// StaticKey is a Keyring holding a single key.
+type StaticKey []byte
+
+func (k StaticKey) CurrentKey(context.Context) (string, []byte, error) {
+	return "", k, nil
+}
+
+func (k StaticKey) Key(_ context.Context, id string) ([]byte, error) {
+	if id != "" {
+		return nil, ErrUnknownKey
+	}
+	return k, nil
+}
+
+// NewEncryptedStore wraps store so that values are encrypted with AES-GCM
+// before they reach it, e.g. to keep the reasons and metadata of a
+// persisted mirror unreadable on disk. Keys are not encrypted, and carry
+// the jwt IDs of revocations. The result implements Locker when store does.
+func NewEncryptedStore(store Store, keys Keyring) Store {
+	s := &encryptedStore{store: store, keys: keys}
+	if locker, ok := store.(Locker); ok {
+		return &lockingEncryptedStore{encryptedStore: s, Locker: locker}
+	}
+	return s
+}
+
+type encryptedStore struct {
+	store Store
+	keys  Keyring
+}
+
+type lockingEncryptedStore struct {
+	*encryptedStore
+	Locker
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts value as the key ID's length and bytes, the nonce, and the
+// ciphertext. The store key is authenticated too, so values cannot be moved
+// between keys.
+func (s *encryptedStore) seal(ctx context.Context, key string, value []byte) ([]byte, error) {
+	id, k, err := s.keys.CurrentKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > 255 {
+		return nil, fmt.Errorf("jwt-revoke: key ID %q is longer than 255 bytes", id)
+	}
+	gcm, err := newGCM(k)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 1+len(id)+gcm.NonceSize(), 1+len(id)+gcm.NonceSize()+len(value)+gcm.Overhead())
+	out[0] = byte(len(id))
+	copy(out[1:], id)
+	nonce := out[1+len(id):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(out, nonce, value, []byte(key)), nil
+}
+
+func (s *encryptedStore) open(ctx context.Context, key string, sealed []byte) ([]byte, error) {
+	if len(sealed) == 0 || len(sealed) < 1+int(sealed[0]) {
+		return nil, fmt.Errorf("jwt-revoke: value of %q is not encrypted", key)
+	}
+	id, rest := string(sealed[1:1+sealed[0]]), sealed[1+sealed[0]:]
+	k, err := s.keys.Key(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(k)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, fmt.Errorf("jwt-revoke: value of %q is not encrypted", key)
+	}
+	value, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("jwt-revoke: decrypting %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func (s *encryptedStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	sealed, ok, err := s.store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	value, err := s.open(ctx, key, sealed)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *encryptedStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	sealed, err := s.seal(ctx, key, value)
+	if err != nil {
+		return err
+	}
+	return s.store.Set(ctx, key, sealed, ttl)
+}
+
+func (s *encryptedStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+func (s *encryptedStore) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	return s.store.Scan(ctx, prefix, func(key string, sealed []byte) error {
+		value, err := s.open(ctx, key, sealed)
+		if err != nil {
+			return err
+		}
+		return fn(key, value)
+	})
+}
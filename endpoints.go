@@ -0,0 +1,162 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailoverThreshold = 3
+	defaultProbeInterval     = 30 * time.Second
+)
+
+// WithEndpoints configures a primary base URL plus regional fallbacks. The
+// client fails over on connection errors or a streak of 5xx responses, and
+// probes the primary in the background to fail back once it recovers.
+func WithEndpoints(primary string, fallbacks ...string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(primary, "/")
+		c.endpoints = &endpointPool{threshold: defaultFailoverThreshold}
+		for _, e := range append([]string{primary}, fallbacks...) {
+			c.endpoints.urls = append(c.endpoints.urls, strings.TrimRight(e, "/"))
+		}
+		c.endpoints.stats = make([]endpointStats, len(c.endpoints.urls))
+	}
+}
+
+// WithFailbackProbeInterval sets how often the primary endpoint is probed
+// while the client is failed over, or every endpoint with
+// WithLatencyRouting. Only meaningful with WithEndpoints.
+func WithFailbackProbeInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		if interval > 0 {
+			c.probeInterval = interval
+		}
+	}
+}
+
+// endpointPool is the failover state, shared by copies made with With.
+type endpointPool struct {
+	mu        sync.Mutex
+	urls      []string
+	active    int
+	failures  int
+	threshold int
+	probing   bool
+
+	// stats and latencyProbes serve WithLatencyRouting.
+	stats         []endpointStats
+	latencyProbes sync.Once
+}
+
+func (p *endpointPool) current() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active, p.urls[p.active]
+}
+
+// recordSuccess resets the failure count of endpoint idx. latency is only
+// sampled when sample is set: long polls and bulk transfers take as long
+// as their wait or payload, not the endpoint's responsiveness.
+func (p *endpointPool) recordSuccess(idx int, latency time.Duration, sample bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if sample {
+		p.stats[idx].observe(latency, false)
+	}
+	if idx == p.active {
+		p.failures = 0
+	}
+}
+
+// recordFailure counts a failure against endpoint idx and reports whether the
+// pool moved on to the next endpoint. Connection errors fail over immediately.
+func (p *endpointPool) recordFailure(idx int, connErr bool) (failedOver bool, startProbe bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if idx != p.active {
+		return false, false
+	}
+	p.failures++
+	if !connErr && p.failures < p.threshold {
+		return false, false
+	}
+	p.failures = 0
+	p.active = (p.active + 1) % len(p.urls)
+	if p.active != 0 && !p.probing {
+		p.probing = true
+		return true, true
+	}
+	return true, false
+}
+
+func (p *endpointPool) failBack() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = 0
+	p.failures = 0
+	p.probing = false
+}
+
+func (c *Client) endpointFailed(ctx context.Context, idx int, connErr bool) {
+	if c.endpoints == nil {
+		return
+	}
+	if c.latencyRouting {
+		// Routing moves away from the endpoint once its error rate is high.
+		c.endpoints.observe(idx, 0, true)
+		return
+	}
+	failedOver, startProbe := c.endpoints.recordFailure(idx, connErr)
+	if !failedOver {
+		return
+	}
+	_, next := c.endpoints.current()
+	c.log(ctx, slog.LevelWarn, "jwtrevoke: failing over to next endpoint",
+		"from", c.endpoints.urls[idx], "to", next)
+	if startProbe {
+		go c.probePrimary()
+	}
+}
+
+// rewriteURL points req at endpoint, preserving the path below the base URL.
+func (c *Client) rewriteURL(req *http.Request, endpoint string) {
+	raw := req.URL.String()
+	for _, base := range c.endpoints.urls {
+		if strings.HasPrefix(raw, base) {
+			u, err := url.Parse(endpoint + strings.TrimPrefix(raw, base))
+			if err == nil {
+				req.URL = u
+				req.Host = ""
+			}
+			return
+		}
+	}
+}
+
+func (c *Client) probePrimary() {
+	ticker := time.NewTicker(c.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.lifecycle.ctx.Done():
+			return
+		}
+		if c.primaryHealthy() {
+			c.endpoints.failBack()
+			c.log(context.Background(), slog.LevelInfo, "jwtrevoke: primary endpoint recovered, failing back",
+				"endpoint", c.endpoints.urls[0])
+			return
+		}
+	}
+}
+
+func (c *Client) primaryHealthy() bool {
+	return c.endpointHealthy(0)
+}
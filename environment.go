@@ -0,0 +1,34 @@
+package jwtrevokeapi
+
+type Environment string
+
+const (
+	EnvironmentProduction Environment = "production"
+	EnvironmentSandbox    Environment = "sandbox"
+)
+
+const (
+	productionBaseURL = "https://api.jwtrevoke.com"
+	sandboxBaseURL    = "https://sandbox.api.jwtrevoke.com"
+)
+
+// WithEnvironment selects the API environment. Sandbox requests go to the
+// sandbox API and carry an X-JWTRevoke-Environment header so they can never
+// be mistaken for production traffic.
+func WithEnvironment(env Environment) ClientOption {
+	return func(c *Client) {
+		c.environment = env
+		switch env {
+		case EnvironmentSandbox:
+			c.baseURL = sandboxBaseURL
+		case EnvironmentProduction:
+			c.baseURL = productionBaseURL
+		}
+	}
+}
+
+// NewSandboxClient returns a client for the sandbox environment, intended for
+// CI and integration tests.
+func NewSandboxClient(apiKey string, options ...ClientOption) *Client {
+	return NewClient(apiKey, append([]ClientOption{WithEnvironment(EnvironmentSandbox)}, options...)...)
+}
@@ -0,0 +1,75 @@
+// Package eventbridge republishes revocation events from one upstream
+// subscription to an internal message bus, so many services can fan out
+// from a single connection to the API. The natsbridge and kafkabridge
+// packages provide publishers for NATS and Kafka.
+package eventbridge
+
+import (
+	"context"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+const (
+	minPublishBackoff = 500 * time.Millisecond
+	maxPublishBackoff = 30 * time.Second
+)
+
+// Publisher delivers one event to the message bus.
+type Publisher interface {
+	Publish(ctx context.Context, ev jwtrevokeapi.RevocationEvent) error
+}
+
+type Options struct {
+	// Since is where the subscription starts. Defaults to now.
+	Since time.Time
+	// OnPublishError is called each time publishing an event fails, before
+	// it is retried.
+	OnPublishError func(ev jwtrevokeapi.RevocationEvent, err error)
+	// OnPublished is called after each event is delivered, e.g. to persist
+	// ev.OccurredAt as the next Since.
+	OnPublished func(ev jwtrevokeapi.RevocationEvent)
+}
+
+// Run subscribes to revocation events and publishes each one, in order,
+// until ctx is done or the subscription ends. An event that cannot be
+// published is retried with backoff rather than dropped, so a bus outage
+// delays events but does not lose them.
+func Run(ctx context.Context, client *jwtrevokeapi.Client, pub Publisher, opts Options) error {
+	if opts.Since.IsZero() {
+		opts.Since = time.Now()
+	}
+	sub := client.Revocations.Subscribe(ctx, opts.Since)
+	for ev := range sub.Events() {
+		if err := publish(ctx, pub, ev, opts.OnPublishError); err != nil {
+			return err
+		}
+		if opts.OnPublished != nil {
+			opts.OnPublished(ev)
+		}
+	}
+	return sub.Err()
+}
+
+func publish(ctx context.Context, pub Publisher, ev jwtrevokeapi.RevocationEvent, onError func(jwtrevokeapi.RevocationEvent, error)) error {
+	backoff := minPublishBackoff
+	for {
+		err := pub.Publish(ctx, ev)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if onError != nil {
+			onError(ev, err)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(2*backoff, maxPublishBackoff)
+	}
+}
@@ -0,0 +1,107 @@
+package jwtrevokeapi
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// eventBusDedupSize is how many recent events the bus remembers, so that
+// mirror sync overlap and several feeds do not deliver an event twice.
+const eventBusDedupSize = 4096
+
+// eventBus fans revocation events out to the handlers registered with
+// OnRevocation. It is shared by a client and its With copies.
+type eventBus struct {
+	mu       sync.Mutex
+	nextID   int
+	handlers map[int]func(RevocationEvent)
+
+	seen  map[string]bool
+	order []string
+
+	// coveredFrom and coveredTo bound the span in which feeds have
+	// published every event without a gap.
+	coveredFrom, coveredTo time.Time
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{handlers: map[int]func(RevocationEvent){}, seen: map[string]bool{}}
+}
+
+// OnRevocation registers fn to be called for every revocation event the
+// client observes: those applied by a Mirror's syncs and those delivered by
+// Subscribe. Each event reaches fn once, however many feeds saw it. fn runs
+// on the feed's goroutine, so it should return quickly. The returned func
+// unregisters fn.
+func (c *Client) OnRevocation(fn func(RevocationEvent)) (unsubscribe func()) {
+	b := c.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+func (b *eventBus) publish(events []RevocationEvent) {
+	b.mu.Lock()
+	if len(b.handlers) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	var fresh []RevocationEvent
+	for _, ev := range events {
+		key := fmt.Sprintf("%s %s %s %d", ev.Type, ev.Token.ID, ev.Token.JwtID, ev.OccurredAt.UnixNano())
+		if b.seen[key] {
+			continue
+		}
+		b.seen[key] = true
+		b.order = append(b.order, key)
+		if len(b.order) > eventBusDedupSize {
+			delete(b.seen, b.order[0])
+			b.order = b.order[1:]
+		}
+		fresh = append(fresh, ev)
+	}
+	handlers := make([]func(RevocationEvent), 0, len(b.handlers))
+	for _, fn := range b.handlers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.Unlock()
+
+	for _, ev := range fresh {
+		for _, fn := range handlers {
+			fn(ev)
+		}
+	}
+}
+
+// covered records that a feed has published every event between from and
+// to, so the span feeds have covered can be extended.
+func (b *eventBus) covered(from, to time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.coveredTo.IsZero() || from.After(b.coveredTo) {
+		b.coveredFrom, b.coveredTo = from, to
+		return
+	}
+	if from.Before(b.coveredFrom) {
+		b.coveredFrom = from
+	}
+	if to.After(b.coveredTo) {
+		b.coveredTo = to
+	}
+}
+
+// covers reports whether feeds have published every event since t, up to
+// at most lag before now.
+func (b *eventBus) covers(t, now time.Time, lag time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.coveredTo.IsZero() && !b.coveredFrom.After(t) && now.Sub(b.coveredTo) <= lag
+}
@@ -0,0 +1,25 @@
+// Package expvarstats publishes a client's RuntimeStats through expvar, for
+// services that use the standard library's /debug/vars endpoint. It is a
+// separate package because importing expvar registers that endpoint on
+// http.DefaultServeMux.
+package expvarstats
+
+import (
+	"expvar"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+// WithExpvar publishes the client's RuntimeStats as the expvar variable
+// name, e.g. "jwtrevoke". Names are global to the process: if name is
+// already published, the existing variable is kept.
+func WithExpvar(name string) jwtrevokeapi.ClientOption {
+	return func(c *jwtrevokeapi.Client) {
+		if expvar.Get(name) != nil {
+			return
+		}
+		expvar.Publish(name, expvar.Func(func() any {
+			return c.RuntimeStats()
+		}))
+	}
+}
@@ -0,0 +1,195 @@
+// Package extauthz implements Envoy's external authorization gRPC service on
+// top of a jwtrevokeapi.RevocationChecker, typically a Cache or Mirror, so
+// Envoy and Istio sidecars can reject revoked tokens at the mesh layer.
+package extauthz
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	authorizationService  = "envoy.service.auth.v3.Authorization"
+)
+
+var _ authv3.AuthorizationServer = (*Server)(nil)
+
+type Options struct {
+	// JwtID extracts the token ID from a check request. It defaults to the
+	// jti claim of the bearer token in the authorization header. Requests
+	// without one are allowed; authenticating them is left to Envoy's
+	// jwt_authn filter or the upstream.
+	JwtID func(req *authv3.CheckRequest) (string, bool)
+	// SessionID extracts the session ID, checked in addition to the token ID
+	// when the checker implements SessionChecker. It defaults to the sid
+	// claim of the bearer token.
+	SessionID func(req *authv3.CheckRequest) (string, bool)
+	// AllowHeaders are added to allowed requests before they reach the
+	// upstream, e.g. to mark them as checked.
+	AllowHeaders map[string]string
+	// DeniedStatus is the HTTP status returned for revoked tokens. Defaults
+	// to 401.
+	DeniedStatus int
+	// DeniedMessage is the message in the JSON body returned for revoked
+	// tokens. Defaults to "token has been revoked".
+	DeniedMessage string
+	// Healthy reports whether the checker can answer, e.g. whether a Mirror
+	// is fresh enough. Serve polls it every HealthInterval and reports the
+	// result through the standard gRPC health service. Nil means always
+	// healthy.
+	Healthy func(ctx context.Context) error
+	// HealthInterval defaults to 10 seconds.
+	HealthInterval time.Duration
+}
+
+// Server answers Envoy's Check calls. When the revocation status cannot be
+// determined, e.g. because a Cache fails closed, requests are denied with a
+// 503 so Envoy's failure_mode_allow setting does not apply.
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+
+	checker  jwtrevokeapi.RevocationChecker
+	sessions jwtrevokeapi.SessionChecker
+	opts     Options
+	health   *health.Server
+}
+
+func New(checker jwtrevokeapi.RevocationChecker, opts Options) *Server {
+	if opts.JwtID == nil {
+		opts.JwtID = func(req *authv3.CheckRequest) (string, bool) {
+			return jwtrevokeapi.BearerJwtID(httpRequest(req))
+		}
+	}
+	if opts.SessionID == nil {
+		opts.SessionID = func(req *authv3.CheckRequest) (string, bool) {
+			return jwtrevokeapi.BearerSessionID(httpRequest(req))
+		}
+	}
+	if opts.DeniedStatus == 0 {
+		opts.DeniedStatus = http.StatusUnauthorized
+	}
+	if opts.DeniedMessage == "" {
+		opts.DeniedMessage = "token has been revoked"
+	}
+	if opts.HealthInterval <= 0 {
+		opts.HealthInterval = defaultHealthInterval
+	}
+	sessions, _ := checker.(jwtrevokeapi.SessionChecker)
+	return &Server{checker: checker, sessions: sessions, opts: opts, health: health.NewServer()}
+}
+
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	var revoked bool
+	var err error
+	if jwtID, ok := s.opts.JwtID(req); ok {
+		revoked, err = s.checker.IsRevoked(ctx, jwtID)
+	}
+	if sid, ok := s.opts.SessionID(req); ok && s.sessions != nil && !revoked && err == nil {
+		revoked, err = s.sessions.IsSessionRevoked(ctx, sid)
+	}
+	switch {
+	case err != nil:
+		return denied(codes.Unavailable, http.StatusServiceUnavailable, "revocation status unavailable", nil), nil
+	case revoked:
+		return denied(codes.PermissionDenied, s.opts.DeniedStatus, s.opts.DeniedMessage,
+			map[string]string{"www-authenticate": `Bearer error="invalid_token", error_description="token has been revoked"`}), nil
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{
+			OkResponse: &authv3.OkHttpResponse{Headers: headerOptions(s.opts.AllowHeaders)},
+		},
+	}, nil
+}
+
+// Register adds the authorization service and the gRPC health service to
+// gs. Call CheckHealth to update the reported health.
+func (s *Server) Register(gs *grpc.Server) {
+	authv3.RegisterAuthorizationServer(gs, s)
+	healthpb.RegisterHealthServer(gs, s.health)
+	s.CheckHealth(context.Background())
+}
+
+// CheckHealth runs Options.Healthy once and updates the health service.
+func (s *Server) CheckHealth(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if s.opts.Healthy != nil && s.opts.Healthy(ctx) != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(authorizationService, status)
+}
+
+// Serve runs a gRPC server on lis until ctx is done, then stops it
+// gracefully.
+func (s *Server) Serve(ctx context.Context, lis net.Listener, options ...grpc.ServerOption) error {
+	gs := grpc.NewServer(options...)
+	s.Register(gs)
+
+	go func() {
+		ticker := time.NewTicker(s.opts.HealthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				s.health.Shutdown()
+				gs.GracefulStop()
+				return
+			case <-ticker.C:
+				s.CheckHealth(ctx)
+			}
+		}
+	}()
+	if err := gs.Serve(lis); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// httpRequest exposes the checked request's authorization header to the
+// SDK's bearer token helpers. Envoy sends header names in lower case.
+func httpRequest(req *authv3.CheckRequest) *http.Request {
+	h := http.Header{}
+	h.Set("Authorization", req.GetAttributes().GetRequest().GetHttp().GetHeaders()["authorization"])
+	return &http.Request{Header: h}
+}
+
+func denied(code codes.Code, httpStatus int, message string, headers map[string]string) *authv3.CheckResponse {
+	body, _ := json.Marshal(map[string]interface{}{"message": message, "data": nil})
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headers["content-type"] = "application/json"
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: message},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{
+			DeniedResponse: &authv3.DeniedHttpResponse{
+				Status:  &typev3.HttpStatus{Code: typev3.StatusCode(httpStatus)},
+				Headers: headerOptions(headers),
+				Body:    string(body),
+			},
+		},
+	}
+}
+
+func headerOptions(headers map[string]string) []*corev3.HeaderValueOption {
+	var options []*corev3.HeaderValueOption
+	for k, v := range headers {
+		options = append(options, &corev3.HeaderValueOption{Header: &corev3.HeaderValue{Key: k, Value: v}})
+	}
+	return options
+}
@@ -1,3 +1,21 @@
-module https://github.com/jwtrevoke/go-sdk
+module github.com/jwtrevoke/go-sdk
 
-go 1.17 
\ No newline at end of file
+go 1.22
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/net v0.26.0
+	golang.org/x/oauth2 v0.24.0
+	golang.org/x/sync v0.7.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/rogpeppe/go-internal v1.12.0 // indirect
+	github.com/stretchr/testify v1.8.4 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
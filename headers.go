@@ -0,0 +1,60 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithHeader adds a header to every request, for example a routing header
+// required by an egress gateway. Headers the SDK sets itself, such as
+// X-API-Key and User-Agent, are never overridden.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		if c.headers == nil {
+			c.headers = http.Header{}
+		}
+		c.headers.Add(key, value)
+	}
+}
+
+// WithHeaders is WithHeader for several headers at once.
+func WithHeaders(headers map[string]string) ClientOption {
+	return func(c *Client) {
+		for key, value := range headers {
+			WithHeader(key, value)(c)
+		}
+	}
+}
+
+// WithContextHeader sets a header on every request from a value extract
+// finds in the call's context, e.g. a trace or tenant ID put there by
+// inbound middleware, so it is propagated without touching call sites. It
+// takes precedence over WithHeader for the same key; headers the SDK sets
+// itself are never overridden.
+func WithContextHeader(key string, extract func(ctx context.Context) (string, bool)) ClientOption {
+	return func(c *Client) {
+		c.contextHeaders = append(c.contextHeaders, contextHeader{key: http.CanonicalHeaderKey(key), extract: extract})
+	}
+}
+
+type contextHeader struct {
+	key     string
+	extract func(ctx context.Context) (string, bool)
+}
+
+func (c *Client) applyHeaders(req *http.Request) {
+	for _, h := range c.contextHeaders {
+		if _, ok := req.Header[h.key]; ok {
+			continue
+		}
+		if value, ok := h.extract(req.Context()); ok {
+			req.Header[h.key] = []string{value}
+		}
+	}
+	for key, values := range c.headers {
+		if _, ok := req.Header[key]; ok {
+			continue
+		}
+		req.Header[key] = append([]string(nil), values...)
+	}
+}
@@ -0,0 +1,26 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Ping checks that the API is reachable and that the configured API key is
+// accepted. A rejected key is reported as a *ClientError with status 401.
+func (c *Client) Ping(ctx context.Context, opts ...CallOption) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/health", c.baseURL), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
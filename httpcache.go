@@ -0,0 +1,192 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const httpCacheKeyPrefix = "http/"
+
+// httpCacheVary lists the request headers a cached response is keyed by,
+// besides the URL, so responses are never shared between accounts,
+// projects or wire formats.
+var httpCacheVary = []string{apiKeyHeader, "Authorization", "X-Project-Id", "X-Api-Version", "Accept"}
+
+// WithHTTPCache keeps GET responses the API marks cacheable with
+// Cache-Control: max-age in store, and answers repeated reads from it until
+// they go stale, taking the Age header into account. Responses marked
+// no-store or no-cache are not kept. Successful updates, deletes and other
+// unsafe requests drop the cached responses of the resources they change.
+// Entries are keyed by URL and credentials, so a shared store such as
+// redisstore lets instances reuse each other's reads. Listings that span
+// several pages, mirror syncs, change polls and Cache lookups always go to
+// the API, so they never see an older state than the API's.
+func WithHTTPCache(store Store) ClientOption {
+	return func(c *Client) {
+		c.httpCache = store
+	}
+}
+
+// WithCallNoCache skips the WithHTTPCache lookup for a call that must see
+// the latest state. A cacheable response still refreshes the cache.
+func WithCallNoCache() CallOption {
+	return func(cfg *callConfig) {
+		cfg.noCache = true
+	}
+}
+
+// cachedResponse is a response as stored by WithHTTPCache.
+type cachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	// Age is the response's age when it was stored, and MaxAge its freshness
+	// lifetime, both in seconds.
+	Age    int `json:"age"`
+	MaxAge int `json:"max_age"`
+}
+
+// httpCacheKey returns the cache key of req, or "" when it is not cached.
+func (c *Client) httpCacheKey(req *http.Request, cfg *callConfig) string {
+	if c.httpCache == nil || req.Method != http.MethodGet || cfg.ifNoneMatch != "" {
+		return ""
+	}
+	return httpCacheKeyFor(req.URL.RequestURI(), req.Header)
+}
+
+// httpCacheKeyFor returns the cache key of a GET of uri with header.
+func httpCacheKeyFor(uri string, header http.Header) string {
+	h := sha256.New()
+	io.WriteString(h, uri)
+	for _, name := range httpCacheVary {
+		io.WriteString(h, "\x00"+header.Get(name))
+	}
+	return httpCacheKeyPrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// invalidateHTTPCache drops the cached responses a successful unsafe
+// request may have changed, as RFC 9111 section 4.4 asks: its target URI
+// and the resources it is nested in, e.g. the revocation a restore acts on,
+// and the URIs of the Location and Content-Location headers.
+func (c *Client) invalidateHTTPCache(ctx context.Context, req *http.Request, resp *http.Response) {
+	if c.httpCache == nil {
+		return
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return
+	}
+	uris := []string{req.URL.RequestURI()}
+	for p := req.URL.EscapedPath(); strings.Count(p, "/") > 2; {
+		p = p[:strings.LastIndex(p, "/")]
+		uris = append(uris, p)
+	}
+	for _, name := range []string{"Location", "Content-Location"} {
+		v := resp.Header.Get(name)
+		if v == "" {
+			continue
+		}
+		if loc, err := req.URL.Parse(v); err == nil && loc.Host == req.URL.Host {
+			uris = append(uris, loc.RequestURI())
+		}
+	}
+	for _, uri := range uris {
+		if err := c.httpCache.Delete(ctx, httpCacheKeyFor(uri, req.Header)); err != nil {
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: HTTP cache invalidation failed", "error", err)
+			return
+		}
+	}
+}
+
+// lookupHTTPCache returns the fresh cached response to req, if any.
+func (c *Client) lookupHTTPCache(ctx context.Context, key string, req *http.Request) *http.Response {
+	data, ok, err := c.httpCache.Get(ctx, key)
+	if err != nil {
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: HTTP cache lookup failed", "error", err)
+		return nil
+	}
+	var cached cachedResponse
+	if !ok || json.Unmarshal(data, &cached) != nil {
+		return nil
+	}
+	age := cached.Age + int(c.clock.Now().Sub(cached.StoredAt)/time.Second)
+	if age >= cached.MaxAge {
+		return nil
+	}
+
+	c.log(ctx, slog.LevelDebug, "jwtrevoke: served from HTTP cache",
+		"method", req.Method, "path", req.URL.Path, "age", age)
+	header := cached.Header.Clone()
+	header.Set("Age", strconv.Itoa(age))
+	return &http.Response{
+		Status:        strconv.Itoa(cached.StatusCode) + " " + http.StatusText(cached.StatusCode),
+		StatusCode:    cached.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// storeHTTPCache keeps resp under key if its Cache-Control allows, reading
+// its body into memory and replacing it with the copy.
+func (c *Client) storeHTTPCache(ctx context.Context, key string, resp *http.Response) error {
+	maxAge, ok := cacheLifetime(resp.Header)
+	age := max(headerInt(resp.Header, "Age"), 0)
+	if !ok || resp.StatusCode != http.StatusOK || age >= maxAge {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := json.Marshal(cachedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		StoredAt:   c.clock.Now(),
+		Age:        age,
+		MaxAge:     maxAge,
+	})
+	if err == nil {
+		err = c.httpCache.Set(ctx, key, data, time.Duration(maxAge-age)*time.Second)
+	}
+	if err != nil {
+		c.log(ctx, slog.LevelWarn, "jwtrevoke: HTTP cache store failed", "error", err)
+	}
+	return nil
+}
+
+// cacheLifetime returns the max-age of a response, in seconds, and whether
+// its Cache-Control lets a private cache reuse it without revalidation.
+func cacheLifetime(h http.Header) (int, bool) {
+	maxAge := -1
+	for _, directive := range strings.Split(h.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			if n, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				maxAge = n
+			}
+		}
+	}
+	return maxAge, maxAge > 0
+}
@@ -0,0 +1,276 @@
+package jwtrevokeapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const defaultImportBatchSize = 100
+
+type ImportOptions struct {
+	// Format is the input encoding, matching what Export produces.
+	Format ExportFormat
+	// BatchSize is the number of records per bulk revoke call. Defaults to 100.
+	BatchSize int
+	// Concurrency limits the number of bulk calls in flight. Defaults to the
+	// client's WithConcurrency setting.
+	Concurrency int
+	// DefaultReason is used for records without a reason.
+	DefaultReason ReasonCode
+	// Scheduler, if set, paces the bulk calls by the quota and reports
+	// progress; its Concurrency does not apply.
+	Scheduler *BulkScheduler
+}
+
+type ImportError struct {
+	// Line is the 1-based line of the record in the input.
+	Line  int
+	JwtID string
+	Err   error
+}
+
+func (e ImportError) Error() string {
+	if e.JwtID == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d (%s): %v", e.Line, e.JwtID, e.Err)
+}
+
+type ImportReport struct {
+	Total    int
+	Imported int
+	Errors   []ImportError
+}
+
+type importRecord struct {
+	line int
+	req  RevokeRequest
+}
+
+// Import reads revocations from r, submits them in batches with bounded
+// concurrency, and reports per-record failures. The returned error is only
+// set when reading stops early, e.g. on a malformed CSV header or when ctx is
+// cancelled; rejected records are listed in the report instead. Records read
+// before reading stopped are still submitted, or reported with ctx's error.
+func (s *RevocationsService) Import(ctx context.Context, r io.Reader, opts ImportOptions, callOpts ...CallOption) (*ImportReport, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultImportBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = s.client.concurrency
+	}
+
+	report := &ImportReport{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+
+	submit := func(batch []importRecord) {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+
+			reqs := make([]RevokeRequest, len(batch))
+			for i, rec := range batch {
+				reqs[i] = rec.req
+			}
+			var err error
+			if opts.Scheduler != nil {
+				err = opts.Scheduler.do(ctx, func(ctx context.Context) (int, error) {
+					_, err := s.RevokeBatch(ctx, reqs, callOpts...)
+					return len(reqs), err
+				})
+			} else {
+				_, err = s.RevokeBatch(ctx, reqs, callOpts...)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				for _, rec := range batch {
+					report.Errors = append(report.Errors, ImportError{Line: rec.line, JwtID: rec.req.JwtID, Err: err})
+				}
+				return
+			}
+			report.Imported += len(batch)
+		}()
+	}
+
+	var batch []importRecord
+	readErr := readImportRecords(r, opts, func(rec importRecord, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		mu.Lock()
+		report.Total++
+		if err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: rec.line, JwtID: rec.req.JwtID, Err: err})
+			mu.Unlock()
+			return nil
+		}
+		mu.Unlock()
+
+		batch = append(batch, rec)
+		if len(batch) >= opts.BatchSize {
+			submit(batch)
+			batch = nil
+		}
+		return nil
+	})
+	// Records read before reading failed are still sent, unless ctx is done,
+	// so every counted record ends up imported or in Errors.
+	if len(batch) > 0 {
+		if ctx.Err() == nil {
+			submit(batch)
+		} else {
+			mu.Lock()
+			for _, rec := range batch {
+				report.Errors = append(report.Errors, ImportError{Line: rec.line, JwtID: rec.req.JwtID, Err: context.Cause(ctx)})
+			}
+			mu.Unlock()
+		}
+	}
+	wg.Wait()
+
+	return report, readErr
+}
+
+func readImportRecords(r io.Reader, opts ImportOptions, fn func(importRecord, error) error) error {
+	switch opts.Format {
+	case ExportNDJSON:
+		return readNDJSONRecords(r, opts, fn)
+	case ExportCSV:
+		return readCSVRecords(r, opts, fn)
+	default:
+		return fmt.Errorf("jwt-revoke: unsupported import format %q", opts.Format)
+	}
+}
+
+func readNDJSONRecords(r io.Reader, opts ImportOptions, fn func(importRecord, error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var t RevokedToken
+		err := json.Unmarshal([]byte(text), &t)
+		rec := importRecord{line: line, req: revokeRequestFromToken(t, opts)}
+		if err == nil {
+			err = validateImportRecord(rec.req)
+		}
+		if err := fn(rec, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func readCSVRecords(r io.Reader, opts ImportOptions, fn func(importRecord, error) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("jwt-revoke: reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+	if _, ok := columns["jwt_id"]; !ok {
+		return errors.New("jwt-revoke: CSV input has no jwt_id column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	line := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		line++
+		if err != nil {
+			if err := fn(importRecord{line: line}, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		t := RevokedToken{
+			JwtID:        field(record, "jwt_id"),
+			Reason:       ReasonCode(field(record, "reason")),
+			ReasonDetail: field(record, "reason_detail"),
+			FamilyID:     field(record, "family_id"),
+			KeyID:        field(record, "key_id"),
+		}
+		var parseErr error
+		t.Metadata, parseErr = decodeMetadata(field(record, "metadata"))
+		if v := field(record, "expiry_date"); v != "" && parseErr == nil {
+			var expiry Timestamp
+			if expiry, parseErr = ParseTimestamp(v); parseErr == nil {
+				t.ExpiryDate = &expiry
+			}
+		}
+		if v := field(record, "effective_at"); v != "" && parseErr == nil {
+			var at Timestamp
+			if at, parseErr = ParseTimestamp(v); parseErr == nil {
+				t.EffectiveAt = &at
+			}
+		}
+		rec := importRecord{line: line, req: revokeRequestFromToken(t, opts)}
+		if parseErr == nil {
+			parseErr = validateImportRecord(rec.req)
+		}
+		if err := fn(rec, parseErr); err != nil {
+			return err
+		}
+	}
+}
+
+func revokeRequestFromToken(t RevokedToken, opts ImportOptions) RevokeRequest {
+	reason := t.Reason
+	if reason == "" {
+		reason = opts.DefaultReason
+	}
+	req := RevokeRequest{
+		JwtID:        t.JwtID,
+		Reason:       reason,
+		ReasonDetail: t.ReasonDetail,
+		Metadata:     t.Metadata,
+		FamilyID:     t.FamilyID,
+		KeyID:        t.KeyID,
+	}
+	if !t.Permanent() {
+		expiry := t.ExpiryDate.Time
+		req.ExpiryDate = &expiry
+	}
+	if t.EffectiveAt != nil {
+		at := t.EffectiveAt.Time
+		req.EffectiveAt = &at
+	}
+	return req
+}
+
+func validateImportRecord(req RevokeRequest) error {
+	if req.JwtID == "" {
+		return errors.New("missing jwt_id")
+	}
+	return req.Validate()
+}
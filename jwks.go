@@ -0,0 +1,282 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidTokenSignature = errors.New("jwt-revoke: invalid token signature")
+
+// KeySet verifies the signature of a compact JWT and returns its payload. It
+// has the same shape as the KeySet interface of github.com/coreos/go-oidc,
+// so an *oidc.RemoteKeySet can be used wherever a KeySet is expected.
+type KeySet interface {
+	VerifySignature(ctx context.Context, jwt string) (payload []byte, err error)
+}
+
+// jwksRefetchInterval bounds how often an unknown kid triggers a refetch.
+const jwksRefetchInterval = time.Minute
+
+// RemoteKeySet verifies signatures against a JSON Web Key Set fetched from
+// a URL, such as an OpenID provider's jwks_uri. Keys are cached and fetched
+// again when a token names a key ID the set does not have yet. RS256/384/512,
+// PS256/384/512, ES256/384/512, and EdDSA are supported. A token's alg must
+// suit the key's type and, when the key declares one, equal its alg.
+type RemoteKeySet struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    []jsonWebKey
+	fetched time.Time
+}
+
+type jsonWebKey struct {
+	kid string
+	// alg is the key's "alg" member. When set, only tokens signed with that
+	// algorithm verify, so a token cannot pick a weaker or different scheme
+	// for the key.
+	alg string
+	key crypto.PublicKey
+}
+
+// jwsKeyTypes maps each supported JWS algorithm to the JWK key type it
+// signs with.
+var jwsKeyTypes = map[string]string{
+	"RS256": "RSA", "RS384": "RSA", "RS512": "RSA",
+	"PS256": "RSA", "PS384": "RSA", "PS512": "RSA",
+	"ES256": "EC", "ES384": "EC", "ES512": "EC",
+	"EdDSA": "OKP",
+}
+
+func NewRemoteKeySet(jwksURL string) *RemoteKeySet {
+	return &RemoteKeySet{url: jwksURL, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (k *RemoteKeySet) VerifySignature(ctx context.Context, token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("jwt-revoke: malformed JWT")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("jwt-revoke: malformed JWT header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, errors.New("jwt-revoke: malformed JWT header")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidTokenSignature
+	}
+
+	keys, err := k.keysFor(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signed := []byte(parts[0] + "." + parts[1])
+	for _, key := range keys {
+		if key.alg != "" && key.alg != header.Alg {
+			continue
+		}
+		if verifyJWS(header.Alg, key.key, signed, sig) {
+			return base64.RawURLEncoding.DecodeString(parts[1])
+		}
+	}
+	return nil, ErrInvalidTokenSignature
+}
+
+// keysFor returns the cached keys matching kid, refetching the set when
+// none match and the last fetch is old enough.
+func (k *RemoteKeySet) keysFor(ctx context.Context, kid string) ([]jsonWebKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if matched := matchKeys(k.keys, kid); len(matched) > 0 {
+		return matched, nil
+	}
+	if !k.fetched.IsZero() && time.Since(k.fetched) < jwksRefetchInterval {
+		return nil, fmt.Errorf("jwt-revoke: no key %q in %s", kid, k.url)
+	}
+	keys, err := k.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	k.keys, k.fetched = keys, time.Now()
+	if matched := matchKeys(keys, kid); len(matched) > 0 {
+		return matched, nil
+	}
+	return nil, fmt.Errorf("jwt-revoke: no key %q in %s", kid, k.url)
+}
+
+func matchKeys(keys []jsonWebKey, kid string) []jsonWebKey {
+	if kid == "" {
+		return keys
+	}
+	var matched []jsonWebKey
+	for _, key := range keys {
+		if key.kid == kid {
+			matched = append(matched, key)
+		}
+	}
+	return matched
+}
+
+func (k *RemoteKeySet) fetch(ctx context.Context) ([]jsonWebKey, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwt-revoke: fetching %s: unexpected status code: %d", k.url, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("jwt-revoke: decoding %s: %w", k.url, err)
+	}
+
+	var keys []jsonWebKey
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if jwk.Alg != "" && jwsKeyTypes[jwk.Alg] != jwk.Kty {
+			continue
+		}
+		var key crypto.PublicKey
+		switch jwk.Kty {
+		case "RSA":
+			n, e := decodeBigInt(jwk.N), decodeBigInt(jwk.E)
+			if n == nil || e == nil || !e.IsInt64() {
+				continue
+			}
+			key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			curve := ecCurve(jwk.Crv)
+			x, y := decodeBigInt(jwk.X), decodeBigInt(jwk.Y)
+			if curve == nil || x == nil || y == nil || !curve.IsOnCurve(x, y) {
+				continue
+			}
+			key = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		case "OKP":
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if jwk.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+				continue
+			}
+			key = ed25519.PublicKey(x)
+		default:
+			continue
+		}
+		keys = append(keys, jsonWebKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	return keys, nil
+}
+
+func decodeBigInt(s string) *big.Int {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func ecCurve(crv string) elliptic.Curve {
+	switch crv {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+// verifyJWS checks sig over signed for the JWS algorithm alg, which must be
+// one for key's type. Unknown algorithms, including "none", never verify.
+func verifyJWS(alg string, key crypto.PublicKey, signed, sig []byte) bool {
+	if _, ok := jwsKeyTypes[alg]; !ok {
+		return false
+	}
+	if key, ok := key.(ed25519.PublicKey); ok {
+		return alg == "EdDSA" && ed25519.Verify(key, signed, sig)
+	}
+
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			return rsa.VerifyPKCS1v15(key, hash, digest, sig) == nil
+		case "PS":
+			return rsa.VerifyPSS(key, hash, digest, sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if alg[:2] != "ES" || key.Curve != esCurves[alg] || len(sig) != 2*size {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+var esCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
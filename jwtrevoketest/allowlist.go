@@ -0,0 +1,102 @@
+package jwtrevoketest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+func (b *backend) allow(req jwtrevokeapi.AllowRequest) jwtrevokeapi.AllowedToken {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := jwtrevokeapi.AllowedToken{
+		JwtID:     req.JwtID,
+		AllowedAt: jwtrevokeapi.Timestamp{Time: b.now().UTC()},
+		Metadata:  req.Metadata,
+	}
+	if req.ExpiryDate != nil {
+		t.ExpiryDate = &jwtrevokeapi.Timestamp{Time: req.ExpiryDate.UTC()}
+	}
+	b.allowed[req.JwtID] = t
+	return t
+}
+
+func (b *backend) getAllowed(jwtID string) (jwtrevokeapi.AllowedToken, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.allowed[jwtID]
+	if !ok {
+		return jwtrevokeapi.AllowedToken{}, &jwtrevokeapi.ClientError{StatusCode: 404, Message: "allowlist entry not found"}
+	}
+	return t, nil
+}
+
+func (b *backend) disallow(jwtID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.allowed[jwtID]; !ok {
+		return &jwtrevokeapi.ClientError{StatusCode: 404, Message: "allowlist entry not found"}
+	}
+	delete(b.allowed, jwtID)
+	return nil
+}
+
+func (b *backend) listAllowed() []jwtrevokeapi.AllowedToken {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tokens := make([]jwtrevokeapi.AllowedToken, 0, len(b.allowed))
+	for _, t := range b.allowed {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].JwtID < tokens[j].JwtID })
+	return tokens
+}
+
+func (s *Server) handleAllow(w http.ResponseWriter, r *http.Request) {
+	var req jwtrevokeapi.AllowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.JwtID == "" {
+		writeError(w, http.StatusBadRequest, "jwtId is required")
+		return
+	}
+	if dryRun(r) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"token": jwtrevokeapi.AllowedToken{JwtID: req.JwtID}})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"token": s.b.allow(req)})
+}
+
+func (s *Server) handleGetAllowed(w http.ResponseWriter, r *http.Request) {
+	t, err := s.b.getAllowed(r.PathValue("jwtID"))
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"token": t})
+}
+
+func (s *Server) handleDisallow(w http.ResponseWriter, r *http.Request) {
+	jwtID := r.PathValue("jwtID")
+	if dryRun(r) {
+		if _, err := s.b.getAllowed(jwtID); err != nil {
+			writeClientError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"deleted": false})
+		return
+	}
+	if err := s.b.disallow(jwtID); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleListAllowed(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"tokens": s.b.listAllowed()})
+}
@@ -0,0 +1,391 @@
+// Package jwtrevoketest provides test doubles for code built on the
+// jwtrevokeapi client: an in-memory Fake with the same methods as
+// *jwtrevokeapi.Client, and a Server that mimics the HTTP API.
+package jwtrevoketest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+// backend is the in-memory revocation store shared by Fake and Server.
+type backend struct {
+	mu       sync.Mutex
+	tokens   map[string]jwtrevokeapi.RevokedToken
+	deleted  map[string]jwtrevokeapi.RevokedToken
+	sessions map[string]jwtrevokeapi.RevokedSession
+	allowed  map[string]jwtrevokeapi.AllowedToken
+	events   []jwtrevokeapi.RevocationEvent
+	nextID   int
+	now      func() time.Time
+}
+
+func newBackend() *backend {
+	return &backend{
+		tokens:   make(map[string]jwtrevokeapi.RevokedToken),
+		deleted:  make(map[string]jwtrevokeapi.RevokedToken),
+		sessions: make(map[string]jwtrevokeapi.RevokedSession),
+		allowed:  make(map[string]jwtrevokeapi.AllowedToken),
+		now:      time.Now,
+	}
+}
+
+func (b *backend) revoke(req jwtrevokeapi.RevokeRequest) (jwtrevokeapi.RevokedToken, error) {
+	if req.JwtID == "" {
+		return jwtrevokeapi.RevokedToken{}, &jwtrevokeapi.ClientError{
+			StatusCode:  400,
+			Message:     "jwtId is required",
+			Code:        jwtrevokeapi.CodeValidationFailed,
+			FieldErrors: []jwtrevokeapi.FieldError{{Field: "jwtId", Message: "is required"}},
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.revokeLocked(req), nil
+}
+
+func (b *backend) revokeLocked(req jwtrevokeapi.RevokeRequest) jwtrevokeapi.RevokedToken {
+	b.nextID++
+	t := jwtrevokeapi.RevokedToken{
+		ID:           fmt.Sprintf("rev_%d", b.nextID),
+		JwtID:        req.JwtID,
+		Reason:       req.Reason,
+		ReasonDetail: req.ReasonDetail,
+		Metadata:     req.Metadata,
+		FamilyID:     req.FamilyID,
+		KeyID:        req.KeyID,
+		RevokedAt:    jwtrevokeapi.Timestamp{Time: b.now().UTC()},
+	}
+	if req.ExpiryDate != nil {
+		t.ExpiryDate = &jwtrevokeapi.Timestamp{Time: *req.ExpiryDate}
+	}
+	if req.EffectiveAt != nil {
+		t.EffectiveAt = &jwtrevokeapi.Timestamp{Time: *req.EffectiveAt}
+	}
+	b.tokens[t.JwtID] = t
+	b.recordLocked(jwtrevokeapi.EventRevoked, t)
+	return t
+}
+
+func (b *backend) get(jwtID string) (jwtrevokeapi.RevokedToken, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tokens[jwtID]
+	if !ok {
+		return jwtrevokeapi.RevokedToken{}, notFound()
+	}
+	return t, nil
+}
+
+func (b *backend) revokeSession(sid string, reason jwtrevokeapi.ReasonCode) jwtrevokeapi.RevokedSession {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[sid]
+	if !ok {
+		s = jwtrevokeapi.RevokedSession{SessionID: sid, Reason: reason, RevokedAt: jwtrevokeapi.Timestamp{Time: b.now().UTC()}}
+		b.sessions[sid] = s
+	}
+	return s
+}
+
+func (b *backend) getSession(sid string) (jwtrevokeapi.RevokedSession, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[sid]
+	if !ok {
+		return jwtrevokeapi.RevokedSession{}, &jwtrevokeapi.ClientError{StatusCode: 404, Message: "session not found"}
+	}
+	return s, nil
+}
+
+func (b *backend) update(jwtID string, update jwtrevokeapi.UpdateRequest) (jwtrevokeapi.RevokedToken, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tokens[jwtID]
+	if !ok {
+		return jwtrevokeapi.RevokedToken{}, notFound()
+	}
+	if update.Reason != nil {
+		t.Reason = *update.Reason
+	}
+	if update.ReasonDetail != nil {
+		t.ReasonDetail = *update.ReasonDetail
+	}
+	if update.ExpiryDate != nil {
+		t.ExpiryDate = &jwtrevokeapi.Timestamp{Time: *update.ExpiryDate}
+	}
+	b.tokens[jwtID] = t
+	b.recordLocked(jwtrevokeapi.EventUpdated, t)
+	return t, nil
+}
+
+func (b *backend) delete(jwtID string, soft bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tokens[jwtID]
+	if !ok {
+		return notFound()
+	}
+	delete(b.tokens, jwtID)
+	if soft {
+		b.deleted[jwtID] = t
+	}
+	b.recordLocked(jwtrevokeapi.EventDeleted, t)
+	return nil
+}
+
+func (b *backend) restore(jwtID string) (jwtrevokeapi.RevokedToken, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.deleted[jwtID]
+	if !ok {
+		return jwtrevokeapi.RevokedToken{}, notFound()
+	}
+	if _, ok := b.tokens[jwtID]; ok {
+		return jwtrevokeapi.RevokedToken{}, &jwtrevokeapi.ClientError{StatusCode: 409, Message: "token is revoked again"}
+	}
+	delete(b.deleted, jwtID)
+	b.tokens[jwtID] = t
+	b.recordLocked(jwtrevokeapi.EventRevoked, t)
+	return t, nil
+}
+
+// purge deletes revocations that expired before before. A dry run only
+// counts them.
+func (b *backend) purge(before time.Time, dryRun bool) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	purged := 0
+	for jwtID, t := range b.tokens {
+		if t.Permanent() || !t.ExpiryDate.Before(before) {
+			continue
+		}
+		purged++
+		if !dryRun {
+			delete(b.tokens, jwtID)
+			b.recordLocked(jwtrevokeapi.EventDeleted, t)
+		}
+	}
+	return purged
+}
+
+func (b *backend) recordLocked(typ jwtrevokeapi.EventType, t jwtrevokeapi.RevokedToken) {
+	b.events = append(b.events, jwtrevokeapi.RevocationEvent{Type: typ, Token: t, OccurredAt: jwtrevokeapi.Timestamp{Time: b.now().UTC()}})
+}
+
+func (b *backend) changes(since time.Time) jwtrevokeapi.ChangeSet {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().UTC()
+	set := jwtrevokeapi.ChangeSet{ServerTime: jwtrevokeapi.Timestamp{Time: now}}
+	for _, ev := range b.events {
+		if ev.OccurredAt.After(since) {
+			set.Events = append(set.Events, ev)
+		}
+	}
+	// Expiry is not a mutation, so its events are derived from the stored
+	// revocations rather than recorded.
+	for _, t := range b.tokens {
+		if !t.Permanent() && t.ExpiryDate.After(since) && !t.ExpiryDate.After(now) {
+			set.Events = append(set.Events, jwtrevokeapi.RevocationEvent{Type: jwtrevokeapi.EventExpired, Token: t, OccurredAt: *t.ExpiryDate})
+		}
+	}
+	sort.SliceStable(set.Events, func(i, j int) bool { return set.Events[i].OccurredAt.Before(set.Events[j].OccurredAt.Time) })
+	return set
+}
+
+// list applies filters, sorting, and offset-based cursors to the stored tokens.
+func (b *backend) list(opts jwtrevokeapi.ListOptions) (jwtrevokeapi.RevocationPage, error) {
+	b.mu.Lock()
+	var matched []jwtrevokeapi.RevokedToken
+	now := b.now()
+	for _, t := range b.tokens {
+		if matches(t, opts, now) {
+			matched = append(matched, t)
+		}
+	}
+	b.mu.Unlock()
+
+	sortTokens(matched, opts.SortBy, opts.SortOrder)
+
+	offset := 0
+	if opts.Cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(opts.Cursor); err != nil || offset < 0 {
+			return jwtrevokeapi.RevocationPage{}, &jwtrevokeapi.ClientError{StatusCode: 400, Message: "invalid cursor"}
+		}
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := len(matched)
+	if opts.Limit > 0 && offset+opts.Limit < end {
+		end = offset + opts.Limit
+	}
+
+	page := jwtrevokeapi.RevocationPage{Tokens: matched[offset:end]}
+	if end < len(matched) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+func matches(t jwtrevokeapi.RevokedToken, opts jwtrevokeapi.ListOptions, now time.Time) bool {
+	switch opts.Status {
+	case jwtrevokeapi.StatusPending:
+		if t.EffectiveAt == nil || !t.EffectiveAt.After(now) {
+			return false
+		}
+	case jwtrevokeapi.StatusExpired:
+		if t.Permanent() || t.ExpiryDate.After(now) {
+			return false
+		}
+	case jwtrevokeapi.StatusActive:
+		if (t.EffectiveAt != nil && t.EffectiveAt.After(now)) || (!t.Permanent() && !t.ExpiryDate.After(now)) {
+			return false
+		}
+	}
+	if opts.Reason != "" && !strings.Contains(strings.ToLower(string(t.Reason)), strings.ToLower(opts.Reason)) {
+		return false
+	}
+	if opts.RevokedByEmail != "" && t.RevokedByEmail != opts.RevokedByEmail {
+		return false
+	}
+	if !t.Metadata.Matches(opts.Labels) {
+		return false
+	}
+	if opts.FamilyID != "" && t.FamilyID != opts.FamilyID {
+		return false
+	}
+	if opts.KeyID != "" && t.KeyID != opts.KeyID {
+		return false
+	}
+	if !opts.RevokedAfter.IsZero() && !t.RevokedAt.After(opts.RevokedAfter) {
+		return false
+	}
+	if !opts.RevokedBefore.IsZero() && !t.RevokedAt.Before(opts.RevokedBefore) {
+		return false
+	}
+	if !opts.ExpiresAfter.IsZero() && !t.Permanent() && !t.ExpiryDate.After(opts.ExpiresAfter) {
+		return false
+	}
+	if !opts.ExpiresBefore.IsZero() && (t.Permanent() || !t.ExpiryDate.Before(opts.ExpiresBefore)) {
+		return false
+	}
+	return true
+}
+
+func sortTokens(tokens []jwtrevokeapi.RevokedToken, by jwtrevokeapi.SortField, order jwtrevokeapi.SortOrder) {
+	less := func(a, b jwtrevokeapi.RevokedToken) bool { return a.JwtID < b.JwtID }
+	switch by {
+	case jwtrevokeapi.SortByRevokedAt:
+		less = func(a, b jwtrevokeapi.RevokedToken) bool { return a.RevokedAt.Before(b.RevokedAt.Time) }
+	case jwtrevokeapi.SortByExpiryDate:
+		// Permanent revocations never expire, so they sort last.
+		less = func(a, b jwtrevokeapi.RevokedToken) bool {
+			return !a.Permanent() && (b.Permanent() || a.ExpiryDate.Before(b.ExpiryDate.Time))
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if order == jwtrevokeapi.SortDescending {
+			return less(tokens[j], tokens[i])
+		}
+		return less(tokens[i], tokens[j])
+	})
+}
+
+func (b *backend) stats() jwtrevokeapi.RevocationStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	var s jwtrevokeapi.RevocationStats
+	for _, t := range b.tokens {
+		s.Total++
+		switch {
+		case t.EffectiveAt != nil && t.EffectiveAt.After(now):
+			s.Pending++
+		case !t.Permanent() && !t.ExpiryDate.After(now):
+			s.Expired++
+		default:
+			s.Active++
+		}
+		if t.RevokedAt.After(now.Add(-24 * time.Hour)) {
+			s.Last24Hours++
+		}
+		if t.RevokedAt.After(now.Add(-7 * 24 * time.Hour)) {
+			s.Last7Days++
+		}
+	}
+	return s
+}
+
+// analytics counts revocations into fixed-width buckets between query.From
+// and query.To. Weeks are aligned to the Unix epoch rather than the calendar.
+func (b *backend) analytics(query jwtrevokeapi.AnalyticsQuery) []jwtrevokeapi.AnalyticsBucket {
+	width := 24 * time.Hour
+	switch query.Granularity {
+	case jwtrevokeapi.GranularityHour:
+		width = time.Hour
+	case jwtrevokeapi.GranularityWeek:
+		width = 7 * 24 * time.Hour
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from, to := query.From, query.To
+	if to.IsZero() {
+		to = b.now()
+	}
+	if from.IsZero() {
+		from = to
+		for _, t := range b.tokens {
+			if t.RevokedAt.Before(from) {
+				from = t.RevokedAt.Time
+			}
+		}
+	}
+
+	buckets := []jwtrevokeapi.AnalyticsBucket{}
+	for start := from.UTC().Truncate(width); start.Before(to); start = start.Add(width) {
+		end := start.Add(width)
+		bucket := jwtrevokeapi.AnalyticsBucket{
+			Start: jwtrevokeapi.Timestamp{Time: start},
+			End:   jwtrevokeapi.Timestamp{Time: end},
+		}
+		for _, t := range b.tokens {
+			if !t.RevokedAt.Before(start) && t.RevokedAt.Before(end) {
+				bucket.Revocations++
+				if bucket.ByReason == nil {
+					bucket.ByReason = map[jwtrevokeapi.ReasonCode]int{}
+				}
+				bucket.ByReason[t.Reason]++
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
+
+func (b *backend) usage() jwtrevokeapi.Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return jwtrevokeapi.Usage{
+		Plan:              "test",
+		PeriodStart:       jwtrevokeapi.Timestamp{Time: start},
+		PeriodEnd:         jwtrevokeapi.Timestamp{Time: start.AddDate(0, 1, 0)},
+		RevocationEntries: int64(len(b.tokens)),
+	}
+}
+
+func notFound() error {
+	return &jwtrevokeapi.ClientError{StatusCode: 404, Message: "revocation not found"}
+}
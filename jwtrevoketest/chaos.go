@@ -0,0 +1,236 @@
+package jwtrevoketest
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ChaosConfig sets which faults a Chaos injects and how often. Rates are
+// fractions of requests between 0 and 1; the zero value injects nothing.
+type ChaosConfig struct {
+	// Latency delays every request; Jitter adds up to as much again at
+	// random.
+	Latency time.Duration
+	Jitter  time.Duration
+	// RateLimitRate answers requests with 429 Too Many Requests, with a
+	// Retry-After of RetryAfter rounded up to seconds.
+	RateLimitRate float64
+	RetryAfter    time.Duration
+	// ServerErrorRate starts bursts of BurstLength consecutive ErrorStatus
+	// responses, by default single 503s.
+	ServerErrorRate float64
+	BurstLength     int
+	ErrorStatus     int
+	// ResetRate fails requests with a connection reset before they reach the
+	// server.
+	ResetRate float64
+	// TruncateRate cuts response bodies off halfway, so reading them fails
+	// with io.ErrUnexpectedEOF.
+	TruncateRate float64
+	// Seed makes the sequence of faults reproducible; zero picks a random
+	// one.
+	Seed int64
+}
+
+// ChaosStats counts the faults a Chaos has injected.
+type ChaosStats struct {
+	Requests     int
+	Delayed      int
+	RateLimited  int
+	ServerErrors int
+	Resets       int
+	Truncated    int
+}
+
+// Chaos injects latency, rate limiting, server error bursts, connection
+// resets and truncated bodies into a client's requests, to test how a
+// service behaves while the API is degraded. Add it to a client with
+// jwtrevokeapi.WithTransportMiddleware(chaos.Wrap); the faults happen before
+// the SDK's retries see the response, so every attempt can be affected.
+type Chaos struct {
+	mu    sync.Mutex
+	cfg   ChaosConfig
+	rand  *rand.Rand
+	burst int
+	stats ChaosStats
+}
+
+// NewChaos returns a Chaos injecting the faults in cfg.
+func NewChaos(cfg ChaosConfig) *Chaos {
+	c := &Chaos{}
+	c.SetConfig(cfg)
+	return c
+}
+
+// SetConfig replaces the faults injected from now on, e.g. to heal the API
+// partway through a test. The random sequence restarts from cfg.Seed.
+func (c *Chaos) SetConfig(cfg ChaosConfig) {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cfg, c.rand, c.burst = cfg, rand.New(rand.NewSource(seed)), 0
+}
+
+func (c *Chaos) Stats() ChaosStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Wrap returns a RoundTripper that injects c's faults into requests sent
+// through next. nil means http.DefaultTransport.
+func (c *Chaos) Wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &chaosTransport{chaos: c, next: next}
+}
+
+type chaosFault int
+
+const (
+	faultNone chaosFault = iota
+	faultReset
+	faultRateLimit
+	faultServerError
+	faultTruncate
+)
+
+// next picks the delay and fault for one request.
+func (c *Chaos) next() (time.Duration, chaosFault, ChaosConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cfg := c.cfg
+	c.stats.Requests++
+
+	delay := cfg.Latency
+	if cfg.Jitter > 0 {
+		delay += time.Duration(c.rand.Int63n(int64(cfg.Jitter)))
+	}
+	if delay > 0 {
+		c.stats.Delayed++
+	}
+
+	fault := faultNone
+	switch {
+	case c.burst > 0:
+		c.burst--
+		fault = faultServerError
+	case c.rand.Float64() < cfg.ResetRate:
+		fault = faultReset
+	case c.rand.Float64() < cfg.RateLimitRate:
+		fault = faultRateLimit
+	case c.rand.Float64() < cfg.ServerErrorRate:
+		c.burst = max(cfg.BurstLength, 1) - 1
+		fault = faultServerError
+	case c.rand.Float64() < cfg.TruncateRate:
+		fault = faultTruncate
+	}
+	switch fault {
+	case faultReset:
+		c.stats.Resets++
+	case faultRateLimit:
+		c.stats.RateLimited++
+	case faultServerError:
+		c.stats.ServerErrors++
+	case faultTruncate:
+		c.stats.Truncated++
+	}
+	return delay, fault, cfg
+}
+
+type chaosTransport struct {
+	chaos *Chaos
+	next  http.RoundTripper
+}
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay, fault, cfg := t.chaos.next()
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			closeBody(req)
+			return nil, req.Context().Err()
+		}
+	}
+
+	switch fault {
+	case faultReset:
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	case faultRateLimit:
+		closeBody(req)
+		rec := httptest.NewRecorder()
+		retryAfter := (cfg.RetryAfter + time.Second - 1) / time.Second
+		rec.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+		writeError(rec, http.StatusTooManyRequests, "rate limit exceeded")
+		return response(rec, req), nil
+	case faultServerError:
+		closeBody(req)
+		status := cfg.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		rec := httptest.NewRecorder()
+		writeError(rec, status, http.StatusText(status))
+		return response(rec, req), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || fault != faultTruncate {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &truncatedBody{Reader: bytes.NewReader(body[:len(body)/2])}
+	return resp, nil
+}
+
+func response(rec *httptest.ResponseRecorder, req *http.Request) *http.Response {
+	resp := rec.Result()
+	resp.Request = req
+	return resp
+}
+
+// closeBody releases the body of a request that is not forwarded, as
+// RoundTrippers must.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// truncatedBody ends in io.ErrUnexpectedEOF, like a connection that dropped
+// mid-response.
+type truncatedBody struct {
+	*bytes.Reader
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return nil
+}
@@ -0,0 +1,341 @@
+package jwtrevoketest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+// Fake is an in-memory stand-in for a client's Revocations sub-client. Its
+// methods have the same signatures and error behavior, including ErrNotFound for missing
+// revocations, but never touch the network. Call options are accepted and
+// ignored.
+type Fake struct {
+	b *backend
+
+	once   sync.Once
+	client *jwtrevokeapi.Client
+}
+
+var _ jwtrevokeapi.RevocationAPI = (*Fake)(nil)
+
+func NewFake() *Fake {
+	return &Fake{b: newBackend()}
+}
+
+// inProcess returns a real client whose requests are served by a Server
+// handler over the fake's backend without a listener. Client-side helpers
+// such as Export and Import run through it so they behave exactly as they do
+// against the API.
+func (f *Fake) inProcess() *jwtrevokeapi.Client {
+	f.once.Do(func() {
+		s := &Server{APIKey: DefaultAPIKey, b: f.b}
+		f.client = jwtrevokeapi.NewClient(DefaultAPIKey,
+			jwtrevokeapi.WithBaseURL("http://jwtrevoketest.invalid"),
+			jwtrevokeapi.WithRateLimitDelay(0),
+			jwtrevokeapi.WithHTTPClient(&http.Client{Transport: handlerTransport{s.handler()}}),
+		)
+	})
+	return f.client
+}
+
+type handlerTransport struct {
+	h http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.h.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// SetNow overrides the clock used for timestamps and pending/expired status.
+func (f *Fake) SetNow(now func() time.Time) {
+	f.b.mu.Lock()
+	f.b.now = now
+	f.b.mu.Unlock()
+}
+
+// Revoke and RevokeBatch run requests that carry a Token through the client
+// so the ID and expiry are derived from its claims exactly as they are
+// against the API.
+func (f *Fake) Revoke(ctx context.Context, req jwtrevokeapi.RevokeRequest, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	if req.Token != "" {
+		return f.inProcess().Revocations.Revoke(ctx, req)
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := f.b.revoke(req)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (f *Fake) RevokeBatch(ctx context.Context, revocations []jwtrevokeapi.RevokeRequest, opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.RevokedToken, error) {
+	for _, req := range revocations {
+		if req.Token != "" {
+			return f.inProcess().Revocations.RevokeBatch(ctx, revocations)
+		}
+	}
+	for _, req := range revocations {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	tokens := make([]jwtrevokeapi.RevokedToken, 0, len(revocations))
+	for _, req := range revocations {
+		t, _ := f.b.revoke(req)
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+// RevokeBySubject, RevokeByIssuer, RevokeByAudience, and RevokeAll succeed
+// without revoking anything, since the fake does not know which tokens exist.
+func (f *Fake) RevokeBySubject(ctx context.Context, sub string, reason jwtrevokeapi.ReasonCode, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ScopedRevocationResult, error) {
+	return &jwtrevokeapi.ScopedRevocationResult{}, nil
+}
+
+// RevokeSession records the session, which GetSession then reports, so a
+// Cache over the fake sees it as revoked.
+func (f *Fake) RevokeSession(ctx context.Context, sid string, reason jwtrevokeapi.ReasonCode, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ScopedRevocationResult, error) {
+	if sid == "" {
+		return nil, &jwtrevokeapi.ValidationError{Field: "sid", Message: "must not be empty"}
+	}
+	f.b.revokeSession(sid, reason)
+	return &jwtrevokeapi.ScopedRevocationResult{}, nil
+}
+
+func (f *Fake) GetSession(ctx context.Context, sid string, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedSession, error) {
+	s, err := f.b.getSession(sid)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (f *Fake) RevokeByIssuer(ctx context.Context, issuer string, reason jwtrevokeapi.ReasonCode, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ScopedRevocationResult, error) {
+	return &jwtrevokeapi.ScopedRevocationResult{}, nil
+}
+
+func (f *Fake) RevokeByAudience(ctx context.Context, audience string, reason jwtrevokeapi.ReasonCode, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ScopedRevocationResult, error) {
+	return &jwtrevokeapi.ScopedRevocationResult{}, nil
+}
+
+func (f *Fake) RevokeAll(ctx context.Context, params jwtrevokeapi.RevokeAllOptions, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ScopedRevocationResult, error) {
+	if !params.Confirm {
+		return nil, jwtrevokeapi.ErrConfirmationRequired
+	}
+	return &jwtrevokeapi.ScopedRevocationResult{}, nil
+}
+
+func (f *Fake) Get(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	t, err := f.b.get(jwtID)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (f *Fake) IsRevoked(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) (bool, error) {
+	t, err := f.Get(ctx, jwtID, opts...)
+	if errors.Is(err, jwtrevokeapi.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	f.b.mu.Lock()
+	now := f.b.now()
+	f.b.mu.Unlock()
+	return t.EffectiveAt == nil || !t.EffectiveAt.After(now), nil
+}
+
+func (f *Fake) Update(ctx context.Context, jwtID string, update jwtrevokeapi.UpdateRequest, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	t, err := f.b.update(jwtID, update)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (f *Fake) Delete(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) error {
+	return f.b.delete(jwtID, false)
+}
+
+func (f *Fake) DeleteMany(ctx context.Context, jwtIDs []string, opts ...jwtrevokeapi.CallOption) error {
+	for _, jwtID := range jwtIDs {
+		if err := f.b.delete(jwtID, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Fake) Purge(ctx context.Context, before time.Time, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.PurgeResult, error) {
+	if before.IsZero() || before.After(f.b.now()) {
+		return nil, &jwtrevokeapi.ValidationError{Field: "before", Message: "must not be zero or in the future"}
+	}
+	return &jwtrevokeapi.PurgeResult{PurgedCount: f.b.purge(before, false)}, nil
+}
+
+func (f *Fake) Allow(ctx context.Context, req jwtrevokeapi.AllowRequest, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.AllowedToken, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	t := f.b.allow(req)
+	return &t, nil
+}
+
+func (f *Fake) Disallow(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) error {
+	return f.b.disallow(jwtID)
+}
+
+func (f *Fake) GetAllowed(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.AllowedToken, error) {
+	t, err := f.b.getAllowed(jwtID)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// IsAllowed makes the fake an AllowChecker for MiddlewareOptions.Allowlist.
+func (f *Fake) IsAllowed(ctx context.Context, jwtID string) (bool, error) {
+	t, err := f.GetAllowed(ctx, jwtID)
+	if errors.Is(err, jwtrevokeapi.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !t.Expired(), nil
+}
+
+func (f *Fake) ListAllowed(ctx context.Context, opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.AllowedToken, error) {
+	return f.b.listAllowed(), nil
+}
+
+func (f *Fake) List(ctx context.Context, params jwtrevokeapi.ListOptions, opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.RevokedToken, error) {
+	params.Cursor, params.Limit = "", 0
+	page, err := f.b.list(params)
+	if err != nil {
+		return nil, err
+	}
+	return page.Tokens, nil
+}
+
+func (f *Fake) ListPage(ctx context.Context, params jwtrevokeapi.ListOptions, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevocationPage, error) {
+	page, err := f.b.list(params)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (f *Fake) Stream(ctx context.Context, params jwtrevokeapi.ListOptions, fn func(jwtrevokeapi.RevokedToken) error, opts ...jwtrevokeapi.CallOption) error {
+	tokens, err := f.List(ctx, params, opts...)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *Fake) Changes(ctx context.Context, since time.Time, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ChangeSet, error) {
+	set := f.b.changes(since)
+	return &set, nil
+}
+
+func (f *Fake) Export(ctx context.Context, w io.Writer, format jwtrevokeapi.ExportFormat, opts ...jwtrevokeapi.CallOption) error {
+	return f.inProcess().Revocations.Export(ctx, w, format)
+}
+
+func (f *Fake) Import(ctx context.Context, r io.Reader, opts jwtrevokeapi.ImportOptions, callOpts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ImportReport, error) {
+	return f.inProcess().Revocations.Import(ctx, r, opts)
+}
+
+func (f *Fake) Stats(ctx context.Context, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevocationStats, error) {
+	s := f.b.stats()
+	return &s, nil
+}
+
+func (f *Fake) Analytics(ctx context.Context, query jwtrevokeapi.AnalyticsQuery, opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.AnalyticsBucket, error) {
+	return f.b.analytics(query), nil
+}
+
+func (f *Fake) Usage(ctx context.Context, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.Usage, error) {
+	u := f.b.usage()
+	return &u, nil
+}
+
+func (f *Fake) Ping(ctx context.Context, opts ...jwtrevokeapi.CallOption) error {
+	return nil
+}
+
+// The methods below match the flat *jwtrevokeapi.Client methods that predate
+// the Revocations sub-client.
+
+// Deprecated: Use Revoke with jwtrevokeapi.NewRevokeRequest instead.
+func (f *Fake) RevokeToken(jwtID string, reason jwtrevokeapi.ReasonCode, expiryDate time.Time, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	return f.Revoke(context.Background(), jwtrevokeapi.NewRevokeRequest(jwtID, reason, expiryDate), opts...)
+}
+
+// Deprecated: Use Get instead.
+func (f *Fake) GetRevokedToken(ctx context.Context, jwtID string, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	return f.Get(ctx, jwtID, opts...)
+}
+
+// Deprecated: Use Update instead.
+func (f *Fake) UpdateRevokedToken(ctx context.Context, jwtID string, update jwtrevokeapi.UpdateRequest, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevokedToken, error) {
+	return f.Update(ctx, jwtID, update, opts...)
+}
+
+// Deprecated: Use Delete instead.
+func (f *Fake) DeleteRevokedToken(jwtID string, opts ...jwtrevokeapi.CallOption) error {
+	return f.Delete(context.Background(), jwtID, opts...)
+}
+
+// Deprecated: Use DeleteMany instead.
+func (f *Fake) DeleteRevokedTokens(ctx context.Context, jwtIDs []string, opts ...jwtrevokeapi.CallOption) error {
+	return f.DeleteMany(ctx, jwtIDs, opts...)
+}
+
+// Deprecated: Use List instead.
+func (f *Fake) ListRevokedTokens(opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.RevokedToken, error) {
+	return f.List(context.Background(), jwtrevokeapi.ListOptions{}, opts...)
+}
+
+// Deprecated: Use List instead.
+func (f *Fake) ListRevokedTokensWithOptions(ctx context.Context, params jwtrevokeapi.ListOptions, opts ...jwtrevokeapi.CallOption) ([]jwtrevokeapi.RevokedToken, error) {
+	return f.List(ctx, params, opts...)
+}
+
+// Deprecated: Use ListPage instead.
+func (f *Fake) ListRevokedTokensPage(ctx context.Context, params jwtrevokeapi.ListOptions, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.RevocationPage, error) {
+	return f.ListPage(ctx, params, opts...)
+}
+
+// Deprecated: Use Stream instead.
+func (f *Fake) StreamRevokedTokens(ctx context.Context, params jwtrevokeapi.ListOptions, fn func(jwtrevokeapi.RevokedToken) error, opts ...jwtrevokeapi.CallOption) error {
+	return f.Stream(ctx, params, fn, opts...)
+}
+
+// Deprecated: Use Changes instead.
+func (f *Fake) ListChanges(ctx context.Context, since time.Time, opts ...jwtrevokeapi.CallOption) (*jwtrevokeapi.ChangeSet, error) {
+	return f.Changes(ctx, since, opts...)
+}
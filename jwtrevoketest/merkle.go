@@ -0,0 +1,126 @@
+package jwtrevoketest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"net/http"
+	"slices"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+// merkleTree is the RFC 6962 tree over the revocations in effect, with
+// leaves sorted by hash.
+type merkleTree struct {
+	leaves [][]byte
+}
+
+func (b *backend) merkleTree() merkleTree {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	var leaves [][]byte
+	for jwtID, t := range b.tokens {
+		if t.EffectiveAt != nil && t.EffectiveAt.After(now) {
+			continue
+		}
+		leaves = append(leaves, leafHash(jwtID))
+	}
+	slices.SortFunc(leaves, bytes.Compare)
+	return merkleTree{leaves: leaves}
+}
+
+// proof returns the leaves proving that key is or is not in the tree.
+func (t merkleTree) proof(key []byte) (bool, []jwtrevokeapi.MerkleLeaf) {
+	i, found := slices.BinarySearchFunc(t.leaves, key, bytes.Compare)
+	var indexes []int
+	switch {
+	case found:
+		indexes = []int{i}
+	case len(t.leaves) == 0:
+	case i == 0:
+		indexes = []int{0}
+	case i == len(t.leaves):
+		indexes = []int{i - 1}
+	default:
+		indexes = []int{i - 1, i}
+	}
+	var leaves []jwtrevokeapi.MerkleLeaf
+	for _, index := range indexes {
+		leaves = append(leaves, jwtrevokeapi.MerkleLeaf{
+			Index:     index,
+			Hash:      t.leaves[index],
+			AuditPath: auditPath(index, t.leaves),
+		})
+	}
+	return found, leaves
+}
+
+func (t merkleTree) root() []byte {
+	if len(t.leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return sum[:]
+	}
+	return treeHash(t.leaves)
+}
+
+// treeHash and auditPath follow RFC 9162, sections 2.1.1 and 2.1.3.1.
+func treeHash(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return nodeHash(treeHash(leaves[:k]), treeHash(leaves[k:]))
+}
+
+func auditPath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if m < k {
+		return append(auditPath(m, leaves[:k]), treeHash(leaves[k:]))
+	}
+	return append(auditPath(m-k, leaves[k:]), treeHash(leaves[:k]))
+}
+
+// splitPoint is the largest power of two smaller than n.
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func leafHash(jwtID string) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write([]byte(jwtID))
+	return h.Sum(nil)
+}
+
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func (s *Server) handleProof(w http.ResponseWriter, r *http.Request) {
+	tree := s.b.merkleTree()
+	revoked, leaves := tree.proof(leafHash(r.PathValue("jwtID")))
+	head := jwtrevokeapi.SignedTreeHead{
+		TreeSize:  len(tree.leaves),
+		RootHash:  tree.root(),
+		Timestamp: jwtrevokeapi.Timestamp{Time: time.Now().UTC().Truncate(time.Second)},
+	}
+	head.Signature = ed25519.Sign(s.SigningKey, jwtrevokeapi.SignedTreeHeadMessage(head.TreeSize, head.Timestamp.Time, head.RootHash))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"revoked": revoked,
+		"proof":   jwtrevokeapi.RevocationProof{TreeHead: head, Leaves: leaves},
+	})
+}
@@ -0,0 +1,628 @@
+package jwtrevoketest
+
+import (
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const DefaultAPIKey = "test-api-key"
+
+// Server is an httptest server that mimics the jwtrevoke HTTP API, including
+// API key checks, cursor pagination, and the API's error shape. Failures and
+// rate limiting can be injected to exercise retry behavior.
+type Server struct {
+	*httptest.Server
+
+	APIKey string
+	// BearerToken, when set, is also accepted as an OAuth2 access token.
+	BearerToken string
+	// SigningKey signs the tree heads of revocation proofs. Pass its public
+	// half to WithSnapshotPublicKey to use IsRevokedVerified.
+	SigningKey ed25519.PrivateKey
+
+	b            *backend
+	mu           sync.Mutex
+	failures     []int
+	requests     []*http.Request
+	quota        *quota
+	capabilities *jwtrevokeapi.Capabilities
+	rejected     map[string]bool
+}
+
+// quota is a fixed request allowance set with SetQuota.
+type quota struct {
+	limit, remaining int
+	reset            time.Time
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer() *Server {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{APIKey: DefaultAPIKey, SigningKey: key, b: newBackend()}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /api/revocations/list", s.handleList)
+	mux.HandleFunc("GET /api/revocations/stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"stats": s.b.stats()})
+	})
+	mux.HandleFunc("GET /api/revocations/analytics", s.handleAnalytics)
+	mux.HandleFunc("GET /api/capabilities", s.handleCapabilities)
+	mux.HandleFunc("GET /api/usage", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"usage": s.b.usage()})
+	})
+	mux.HandleFunc("GET /api/revocations/changes", s.handleChanges)
+	mux.HandleFunc("POST /api/revocations/revoke", s.handleRevoke)
+	mux.HandleFunc("POST /api/revocations/batch", s.handleBatch)
+	mux.HandleFunc("POST /api/revocations/revoke-subject", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/revoke-session", s.handleRevokeSession)
+	mux.HandleFunc("GET /api/revocations/sessions/{sid}", s.handleGetSession)
+	mux.HandleFunc("POST /api/revocations/revoke-family", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/revoke-key", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/revoke-issuer", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/revoke-audience", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/revoke-all", s.handleScoped)
+	mux.HandleFunc("POST /api/revocations/purge", s.handlePurge)
+	mux.HandleFunc("GET /api/revocations/proofs/{jwtID}", s.handleProof)
+	mux.HandleFunc("GET /api/allowlist", s.handleListAllowed)
+	mux.HandleFunc("POST /api/allowlist", s.handleAllow)
+	mux.HandleFunc("GET /api/allowlist/{jwtID}", s.handleGetAllowed)
+	mux.HandleFunc("DELETE /api/allowlist/{jwtID}", s.handleDisallow)
+	mux.HandleFunc("GET /api/revocations/{jwtID}", s.handleGet)
+	mux.HandleFunc("PATCH /api/revocations/{jwtID}", s.handleUpdate)
+	mux.HandleFunc("DELETE /api/revocations/{jwtID}", s.handleDelete)
+	mux.HandleFunc("POST /api/revocations/{jwtID}/restore", s.handleRestore)
+	return s.middleware(mux)
+}
+
+// Client returns a client configured against the server with its API key and
+// no rate limit delay. Additional options are applied afterwards.
+func (s *Server) Client(options ...jwtrevokeapi.ClientOption) *jwtrevokeapi.Client {
+	base := []jwtrevokeapi.ClientOption{
+		jwtrevokeapi.WithBaseURL(s.URL),
+		jwtrevokeapi.WithRateLimitDelay(0),
+	}
+	return jwtrevokeapi.NewClient(s.APIKey, append(base, options...)...)
+}
+
+// Seed stores revocations directly, bypassing HTTP.
+func (s *Server) Seed(revocations ...jwtrevokeapi.RevokeRequest) {
+	for _, req := range revocations {
+		s.b.revoke(req)
+	}
+}
+
+// FailNext makes the next n requests fail with the given HTTP status.
+func (s *Server) FailNext(n int, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		s.failures = append(s.failures, status)
+	}
+}
+
+// RateLimitNext makes the next n requests receive 429 Too Many Requests.
+func (s *Server) RateLimitNext(n int) {
+	s.FailNext(n, http.StatusTooManyRequests)
+}
+
+// SetQuota allows limit requests until reset, after which requests are no
+// longer limited. Until then responses carry X-RateLimit-Limit,
+// X-RateLimit-Remaining, and X-RateLimit-Reset headers, and requests beyond
+// the quota get a 429.
+func (s *Server) SetQuota(limit int, reset time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quota = &quota{limit: limit, remaining: limit, reset: reset}
+}
+
+// DefaultCapabilities is what the server reports until SetCapabilities is
+// called: every transport, batches of 100, and both snapshot formats.
+var DefaultCapabilities = jwtrevokeapi.Capabilities{
+	MaxBatchSize:    100,
+	SnapshotFormats: []string{"sorted_hashes", "cuckoo"},
+	EventTransports: []jwtrevokeapi.EventTransport{jwtrevokeapi.TransportLongPoll, jwtrevokeapi.TransportPolling, jwtrevokeapi.TransportWebhooks},
+}
+
+// SetCapabilities changes what the capabilities endpoint reports, e.g. to
+// simulate a plan without long polling. Batches larger than MaxBatchSize are
+// then rejected. The client caches capabilities, so set them before it
+// first needs them.
+func (s *Server) SetCapabilities(caps jwtrevokeapi.Capabilities) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capabilities = &caps
+}
+
+func (s *Server) currentCapabilities() jwtrevokeapi.Capabilities {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capabilities != nil {
+		return *s.capabilities
+	}
+	return DefaultCapabilities
+}
+
+// RejectRevocations makes batch revocations of the given token IDs fail with
+// a 409. Partial batches report them per item and revoke the rest.
+func (s *Server) RejectRevocations(jwtIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rejected == nil {
+		s.rejected = map[string]bool{}
+	}
+	for _, jwtID := range jwtIDs {
+		s.rejected[jwtID] = true
+	}
+}
+
+func (s *Server) isRejected(jwtID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rejected[jwtID]
+}
+
+// Requests returns the requests received so far, including failed ones.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Clone(r.Context()))
+		var status int
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		if q := s.quota; q != nil && time.Now().After(q.reset) {
+			s.quota = nil
+		}
+		if q := s.quota; q != nil {
+			if q.remaining == 0 && status == 0 {
+				status = http.StatusTooManyRequests
+			} else if q.remaining > 0 {
+				q.remaining--
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(q.limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(q.remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(q.reset.Unix(), 10))
+		}
+		s.mu.Unlock()
+
+		if id := r.Header.Get("X-Request-ID"); id != "" {
+			w.Header().Set("X-Request-ID", id)
+		}
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, status, "rate limit exceeded")
+			return
+		}
+		if status != 0 {
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+		bearer := s.BearerToken != "" && r.Header.Get("Authorization") == "Bearer "+s.BearerToken
+		if !bearer && r.Header.Get("X-API-Key") != s.APIKey {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid gzip body")
+				return
+			}
+			defer zr.Close()
+			r.Body = zr
+		}
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			defer zw.Close()
+			w = gzipResponseWriter{ResponseWriter: w, w: zw}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	w io.Writer
+}
+
+func (g gzipResponseWriter) Write(b []byte) (int, error) {
+	return g.w.Write(b)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := jwtrevokeapi.ListOptions{
+		Status:         jwtrevokeapi.RevocationStatus(q.Get("status")),
+		Reason:         q.Get("reason"),
+		RevokedByEmail: q.Get("revoked_by_email"),
+		RevokedAfter:   parseTime(q.Get("revoked_after")),
+		RevokedBefore:  parseTime(q.Get("revoked_before")),
+		ExpiresAfter:   parseTime(q.Get("expires_after")),
+		ExpiresBefore:  parseTime(q.Get("expires_before")),
+		SortBy:         jwtrevokeapi.SortField(q.Get("sort_by")),
+		SortOrder:      jwtrevokeapi.SortOrder(q.Get("sort_order")),
+		FamilyID:       q.Get("family_id"),
+		KeyID:          q.Get("key_id"),
+		Cursor:         q.Get("cursor"),
+	}
+	opts.Limit, _ = strconv.Atoi(q.Get("limit"))
+	for _, label := range q["label"] {
+		key, value, err := jwtrevokeapi.ParseLabel(label)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if opts.Labels == nil {
+			opts.Labels = map[string]string{}
+		}
+		opts.Labels[key] = value
+	}
+
+	page, err := s.b.list(opts)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	if page.Tokens == nil {
+		page.Tokens = []jwtrevokeapi.RevokedToken{}
+	}
+
+	body, _ := json.Marshal(page)
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeBulk(w, r, http.StatusOK, page)
+}
+
+func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
+	since, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid since")
+		return
+	}
+	set := s.b.changes(since)
+	// Long polling: with wait, hold the request until there are events.
+	wait, _ := strconv.Atoi(r.URL.Query().Get("wait"))
+	deadline := time.Now().Add(time.Duration(wait) * time.Second)
+	for len(set.Events) == 0 && time.Now().Before(deadline) {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(20 * time.Millisecond):
+		}
+		set = s.b.changes(since)
+	}
+	writeJSON(w, http.StatusOK, set)
+}
+
+func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"capabilities": s.currentCapabilities()})
+}
+
+func (s *Server) handleAnalytics(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	buckets := s.b.analytics(jwtrevokeapi.AnalyticsQuery{
+		Granularity: jwtrevokeapi.Granularity(q.Get("granularity")),
+		From:        parseTime(q.Get("from")),
+		To:          parseTime(q.Get("to")),
+	})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": buckets})
+}
+
+// handleScoped accepts subject, family, key, issuer, audience, and account-wide
+// revocations. The fake does not know which tokens were issued, so nothing
+// is counted as revoked.
+func (s *Server) handleScoped(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, jwtrevokeapi.ScopedRevocationResult{})
+}
+
+func (s *Server) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID string                  `json:"sessionId"`
+		Reason    jwtrevokeapi.ReasonCode `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SessionID == "" {
+		writeError(w, http.StatusBadRequest, "sessionId is required")
+		return
+	}
+	if !dryRun(r) {
+		s.b.revokeSession(req.SessionID, req.Reason)
+	}
+	writeJSON(w, http.StatusOK, jwtrevokeapi.ScopedRevocationResult{})
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	session, err := s.b.getSession(r.PathValue("sid"))
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"session": session})
+}
+
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	var req jwtrevokeapi.RevokeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if dryRun(r) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"token": jwtrevokeapi.RevokedToken{JwtID: req.JwtID, Reason: req.Reason, ReasonDetail: req.ReasonDetail, Metadata: req.Metadata}})
+		return
+	}
+	t, err := s.b.revoke(req)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"token": t})
+}
+
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Revocations []jwtrevokeapi.RevokeRequest `json:"revocations"`
+	}
+	if err := decodeBulk(r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body")
+		return
+	}
+	if limit := s.currentCapabilities().MaxBatchSize; limit > 0 && len(body.Revocations) > limit {
+		writeError(w, http.StatusBadRequest, "too many revocations")
+		return
+	}
+	// Partial batches report invalid items instead of failing as a whole;
+	// atomic ones report all of them and revoke nothing.
+	partial := r.URL.Query().Get("partial") == "true"
+	atomic := r.URL.Query().Get("atomic") == "true"
+	itemErrors := []map[string]interface{}{}
+	fieldErrors := []jwtrevokeapi.FieldError{}
+	failed := map[int]bool{}
+	for i, req := range body.Revocations {
+		var status int
+		var message string
+		switch {
+		case req.JwtID == "":
+			status, message = http.StatusBadRequest, "jwtId is required"
+		case s.isRejected(req.JwtID):
+			status, message = http.StatusConflict, "revocation rejected"
+		default:
+			continue
+		}
+		switch {
+		case atomic:
+			fieldErrors = append(fieldErrors, jwtrevokeapi.FieldError{Field: "revocations[" + strconv.Itoa(i) + "]", Message: message, Code: string(statusCode(status))})
+			continue
+		case !partial:
+			writeError(w, status, message)
+			return
+		}
+		failed[i] = true
+		itemErrors = append(itemErrors, map[string]interface{}{"index": i, "code": statusCode(status), "message": message})
+	}
+	if len(fieldErrors) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"message": "batch rejected, nothing was revoked", "code": jwtrevokeapi.CodeValidationFailed, "errors": fieldErrors, "data": nil})
+		return
+	}
+	tokens := []jwtrevokeapi.RevokedToken{}
+	for i, req := range body.Revocations {
+		if failed[i] {
+			continue
+		}
+		if dryRun(r) {
+			tokens = append(tokens, jwtrevokeapi.RevokedToken{JwtID: req.JwtID, Reason: req.Reason, ReasonDetail: req.ReasonDetail, Metadata: req.Metadata})
+			continue
+		}
+		t, _ := s.b.revoke(req)
+		tokens = append(tokens, t)
+	}
+	if partial {
+		writeBulk(w, r, http.StatusOK, map[string]interface{}{"tokens": tokens, "errors": itemErrors})
+		return
+	}
+	writeBulk(w, r, http.StatusOK, map[string]interface{}{"tokens": tokens})
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	// GET patterns also match HEAD.
+	if r.Method == http.MethodHead {
+		s.handleHead(w, r)
+		return
+	}
+	t, err := s.b.get(r.PathValue("jwtID"))
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"token": t})
+}
+
+// handleHead answers with the revocation's status in a header and no body.
+func (s *Server) handleHead(w http.ResponseWriter, r *http.Request) {
+	t, err := s.b.get(r.PathValue("jwtID"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	s.b.mu.Lock()
+	now := s.b.now()
+	s.b.mu.Unlock()
+	status := jwtrevokeapi.StatusActive
+	switch {
+	case t.EffectiveAt != nil && t.EffectiveAt.After(now):
+		status = jwtrevokeapi.StatusPending
+	case !t.Permanent() && !t.ExpiryDate.After(now):
+		status = jwtrevokeapi.StatusExpired
+	}
+	w.Header().Set("X-Revocation-Status", string(status))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	var update jwtrevokeapi.UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	t, err := s.b.update(r.PathValue("jwtID"), update)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"token": t})
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	jwtID := r.PathValue("jwtID")
+	if dryRun(r) {
+		if _, err := s.b.get(jwtID); err != nil {
+			writeClientError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"deleted": false})
+		return
+	}
+	if err := s.b.delete(jwtID, r.URL.Query().Get("soft") == "true"); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	t, err := s.b.restore(r.PathValue("jwtID"))
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"token": t})
+}
+
+func (s *Server) handlePurge(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Before time.Time `json:"before"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Before.IsZero() {
+		writeError(w, http.StatusBadRequest, "before is required")
+		return
+	}
+	if req.Before.After(time.Now()) {
+		writeError(w, http.StatusBadRequest, "before must not be in the future")
+		return
+	}
+	writeJSON(w, http.StatusOK, jwtrevokeapi.PurgeResult{PurgedCount: s.b.purge(req.Before, dryRun(r))})
+}
+
+func dryRun(r *http.Request) bool {
+	return r.Header.Get("X-Dry-Run") == "true"
+}
+
+func parseTime(v string) time.Time {
+	t, _ := time.Parse(time.RFC3339, v)
+	return t
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeBulk answers in msgpack when the request accepts it, as the API does
+// for its bulk endpoints.
+func writeBulk(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	if !strings.Contains(r.Header.Get("Accept"), "application/msgpack") {
+		writeJSON(w, status, v)
+		return
+	}
+	w.Header().Set("Content-Type", "application/msgpack")
+	w.WriteHeader(status)
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.Encode(v)
+}
+
+func decodeBulk(r *http.Request, v interface{}) error {
+	if r.Header.Get("Content-Type") != "application/msgpack" {
+		return json.NewDecoder(r.Body).Decode(v)
+	}
+	dec := msgpack.NewDecoder(r.Body)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"message": message, "code": statusCode(status), "data": nil})
+}
+
+func writeClientError(w http.ResponseWriter, err error) {
+	if ce, ok := err.(*jwtrevokeapi.ClientError); ok {
+		code := ce.Code
+		if code == "" {
+			code = statusCode(ce.StatusCode)
+		}
+		body := map[string]interface{}{"message": ce.Message, "code": code, "data": ce.Data}
+		if len(ce.FieldErrors) > 0 {
+			body["errors"] = ce.FieldErrors
+		}
+		writeJSON(w, ce.StatusCode, body)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// statusCode is the error code the API sends with a status.
+func statusCode(status int) jwtrevokeapi.ErrorCode {
+	switch status {
+	case http.StatusUnprocessableEntity:
+		return jwtrevokeapi.CodeValidationFailed
+	case http.StatusUnauthorized:
+		return jwtrevokeapi.CodeUnauthorized
+	case http.StatusForbidden:
+		return jwtrevokeapi.CodeForbidden
+	case http.StatusNotFound:
+		return jwtrevokeapi.CodeNotFound
+	case http.StatusConflict:
+		return jwtrevokeapi.CodeConflict
+	case http.StatusTooManyRequests:
+		return jwtrevokeapi.CodeRateLimited
+	}
+	if status >= 500 {
+		return jwtrevokeapi.CodeInternal
+	}
+	return jwtrevokeapi.CodeInvalidRequest
+}
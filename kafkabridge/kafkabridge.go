@@ -0,0 +1,37 @@
+// Package kafkabridge publishes revocation events to Kafka for use with
+// eventbridge.Run.
+package kafkabridge
+
+import (
+	"context"
+	"encoding/json"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/jwtrevoke/go-sdk/eventbridge"
+	"github.com/segmentio/kafka-go"
+)
+
+var _ eventbridge.Publisher = (*Publisher)(nil)
+
+// Publisher writes each event as a JSON message keyed by the token's jti,
+// so all events for one token land on the same partition in order. The
+// writer's Topic must be set.
+type Publisher struct {
+	w *kafka.Writer
+}
+
+func New(w *kafka.Writer) *Publisher {
+	return &Publisher{w: w}
+}
+
+func (p *Publisher) Publish(ctx context.Context, ev jwtrevokeapi.RevocationEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return p.w.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(ev.Token.JwtID),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "event_type", Value: []byte(ev.Type)}},
+	})
+}
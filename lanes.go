@@ -0,0 +1,119 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LaneLimits bounds the requests of one priority lane. Zero values leave
+// the lane unbounded.
+type LaneLimits struct {
+	// Concurrency is the most requests the lane keeps in flight; a response
+	// body holds its slot until it is closed.
+	Concurrency int
+	// RequestsPerSecond is the lane's steady request rate, with bursts of
+	// up to Burst requests, by default 1.
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// WithPriorityLanes sends requests through two lanes with limits of their
+// own, so interactive checks such as IsRevoked are never queued behind bulk
+// work sharing the client. Bulk operations use the bulk lane: imports,
+// exports, batches, multi-deletes, purges, streamed listings, mirror syncs,
+// cache warming, subscriptions and BulkScheduler jobs; mark other calls with
+// WithBulkPriority. Everything else is interactive, and with
+// WithAdaptivePacing only bulk requests are paced. Long polls count against
+// a lane's rate but hold no slot while the server keeps them waiting.
+func WithPriorityLanes(interactive, bulk LaneLimits) ClientOption {
+	return func(c *Client) {
+		c.lanes = &priorityLanes{interactive: newLane(interactive), bulk: newLane(bulk)}
+	}
+}
+
+type bulkPriorityKey struct{}
+
+// WithBulkPriority sends a call through the bulk lane of WithPriorityLanes.
+func WithBulkPriority() CallOption {
+	return func(cfg *callConfig) {
+		cfg.bulk = true
+	}
+}
+
+// ContextWithBulkPriority sends the calls made with ctx through the bulk
+// lane, like WithBulkPriority, including those the SDK makes on the
+// caller's behalf.
+func ContextWithBulkPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkPriorityKey{}, true)
+}
+
+func isBulk(ctx context.Context, cfg *callConfig) bool {
+	bulk, _ := ctx.Value(bulkPriorityKey{}).(bool)
+	return cfg.bulk || bulk
+}
+
+// priorityLanes is shared with clients derived by With.
+type priorityLanes struct {
+	interactive, bulk *lane
+}
+
+func (l *priorityLanes) lane(bulk bool) *lane {
+	if bulk {
+		return l.bulk
+	}
+	return l.interactive
+}
+
+type lane struct {
+	slots chan struct{}
+
+	mu       sync.Mutex
+	interval time.Duration
+	burst    time.Duration
+	// next is when the lane's rate budget allows the next request; it lags
+	// the present by up to burst when requests can be sent right away.
+	next time.Time
+}
+
+func newLane(limits LaneLimits) *lane {
+	l := &lane{}
+	if limits.Concurrency > 0 {
+		l.slots = make(chan struct{}, limits.Concurrency)
+	}
+	if limits.RequestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / limits.RequestsPerSecond)
+		l.burst = time.Duration(max(limits.Burst, 1)-1) * l.interval
+	}
+	return l
+}
+
+// acquire waits for the lane's rate budget and a free slot, and returns the
+// function releasing the slot.
+func (l *lane) acquire(ctx context.Context, clock Clock) (func(), error) {
+	if l.interval > 0 {
+		l.mu.Lock()
+		now := clock.Now()
+		slot := l.next
+		if earliest := now.Add(-l.burst); slot.Before(earliest) {
+			slot = earliest
+		}
+		l.next = slot.Add(l.interval)
+		l.mu.Unlock()
+		if wait := slot.Sub(now); wait > 0 {
+			if err := sleepContext(ctx, clock, wait); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l.slots }) }, nil
+}
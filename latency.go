@@ -0,0 +1,144 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// latencyDecay is the weight of each new sample in an endpoint's moving
+	// latency and error averages.
+	latencyDecay = 0.2
+	// unhealthyErrorRate is the error rate above which an endpoint is only
+	// used when every other one is worse.
+	unhealthyErrorRate = 0.5
+)
+
+// WithLatencyRouting makes a client configured with WithEndpoints send each
+// request to the fastest healthy endpoint rather than to the primary. Every
+// request's latency and outcome feeds per-endpoint moving averages, and all
+// endpoints are probed in the background about every
+// WithFailbackProbeInterval, with jitter so a fleet of clients does not
+// probe in step. Endpoints whose recent error rate passes one half are
+// avoided until probes find them healthy again.
+func WithLatencyRouting() ClientOption {
+	return func(c *Client) {
+		c.latencyRouting = true
+	}
+}
+
+// endpointStats is an endpoint's moving latency and error rate.
+type endpointStats struct {
+	latency time.Duration
+	errRate float64
+	// measured is set once a request to the endpoint has succeeded.
+	measured bool
+}
+
+func (s *endpointStats) observe(latency time.Duration, failed bool) {
+	if failed {
+		s.errRate += latencyDecay * (1 - s.errRate)
+		return
+	}
+	s.errRate -= latencyDecay * s.errRate
+	if !s.measured {
+		s.latency, s.measured = latency, true
+		return
+	}
+	s.latency += time.Duration(latencyDecay * float64(latency-s.latency))
+}
+
+func (s endpointStats) better(other endpointStats) bool {
+	healthy, otherHealthy := s.errRate <= unhealthyErrorRate, other.errRate <= unhealthyErrorRate
+	switch {
+	case healthy != otherHealthy:
+		return healthy
+	case !healthy:
+		return s.errRate < other.errRate
+	}
+	return s.latency < other.latency
+}
+
+func (p *endpointPool) observe(idx int, latency time.Duration, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats[idx].observe(latency, failed)
+}
+
+// fastest returns the best measured endpoint, or the primary while none has
+// been measured yet.
+func (p *endpointPool) fastest() (int, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	best := 0
+	for i, s := range p.stats {
+		if s.measured && (!p.stats[best].measured || s.better(p.stats[best])) {
+			best = i
+		}
+	}
+	return best, p.urls[best]
+}
+
+// routeEndpoint picks the endpoint for the next attempt.
+func (c *Client) routeEndpoint() (int, string) {
+	if !c.latencyRouting {
+		return c.endpoints.current()
+	}
+	c.endpoints.latencyProbes.Do(func() { go c.probeLatency() })
+	return c.endpoints.fastest()
+}
+
+// probeLatency measures every endpoint until the client is closed, so that
+// endpoints the traffic avoids are noticed when they become faster or
+// recover.
+func (c *Client) probeLatency() {
+	for {
+		before, _ := c.endpoints.fastest()
+		for i := range c.endpoints.urls {
+			start := time.Now()
+			healthy := c.endpointHealthy(i)
+			c.endpoints.observe(i, time.Since(start), !healthy)
+		}
+		if after, _ := c.endpoints.fastest(); after != before {
+			c.log(context.Background(), slog.LevelInfo, "jwtrevoke: routing to another endpoint",
+				"from", c.endpoints.urls[before], "to", c.endpoints.urls[after])
+		}
+
+		timer := time.NewTimer(time.Duration(float64(c.probeInterval) * (0.5 + rand.Float64())))
+		select {
+		case <-timer.C:
+		case <-c.lifecycle.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// endpointHealthy reports whether endpoint idx answers its health check.
+func (c *Client) endpointHealthy(idx int) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.endpoints.urls[idx]+"/api/health", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	c.applyHeaders(req)
+
+	if err := c.authenticate(ctx, req, ""); err != nil {
+		return false
+	}
+	if err := c.signRequest(req); err != nil {
+		return false
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false
+	}
+	drainBody(resp.Body)
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
@@ -0,0 +1,126 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrResponseTooLarge = errors.New("jwt-revoke: response body exceeds the configured size limit")
+	ErrResponseStalled  = errors.New("jwt-revoke: response stalled longer than the read timeout")
+)
+
+// WithMaxResponseBytes fails reads of any response body, after
+// decompression, that grows beyond n bytes with ErrResponseTooLarge.
+// Zero, the default, means no limit.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithReadTimeout aborts a request when the server sends nothing for d,
+// either while waiting for response headers or between reads of the body.
+// Unlike WithTimeout it does not cap the total duration, so a long export
+// keeps going as long as data is flowing.
+func WithReadTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.readTimeout = d
+	}
+}
+
+// stallWatch cancels a request attempt once it has been idle for the read
+// timeout. A nil watch is valid and does nothing.
+type stallWatch struct {
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	stalled atomic.Bool
+}
+
+func (c *Client) watchStall(req *http.Request) (*http.Request, *stallWatch) {
+	if c.readTimeout <= 0 {
+		return req, nil
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	w := &stallWatch{timeout: c.readTimeout, cancel: cancel}
+	w.timer = time.AfterFunc(c.readTimeout, func() {
+		w.stalled.Store(true)
+		cancel()
+	})
+	return req.WithContext(ctx), w
+}
+
+// err replaces the cancellation caused by a stall with ErrResponseStalled.
+func (w *stallWatch) err(err error) error {
+	if w != nil && err != nil && w.stalled.Load() {
+		return ErrResponseStalled
+	}
+	return err
+}
+
+func (w *stallWatch) stop() {
+	if w != nil {
+		w.timer.Stop()
+		w.cancel()
+	}
+}
+
+func (w *stallWatch) wrap(body io.ReadCloser) io.ReadCloser {
+	if w == nil {
+		return body
+	}
+	return &stallBody{ReadCloser: body, w: w}
+}
+
+type stallBody struct {
+	io.ReadCloser
+	w *stallWatch
+}
+
+func (b *stallBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.w.timer.Reset(b.w.timeout)
+	}
+	return n, b.w.err(err)
+}
+
+func (b *stallBody) Close() error {
+	b.w.stop()
+	return b.ReadCloser.Close()
+}
+
+func (c *Client) limitBody(body io.ReadCloser) io.ReadCloser {
+	if c.maxResponseBytes <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, remaining: c.maxResponseBytes}
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there really is more data beyond the limit.
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,118 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+var sensitiveKeys = []string{"api_key", "apikey", "x-api-key", "authorization", "token", "jwt", "secret", "password"}
+
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			c.logger = nil
+			return
+		}
+		c.logger = slog.New(&redactingHandler{next: logger.Handler(), client: c})
+	}
+}
+
+// logEnabled reports whether c.log would log at level, so hot paths can skip
+// building the arguments.
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, level)
+}
+
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.Log(ctx, level, msg, args...)
+}
+
+// redactString masks the client's API key and anything shaped like a JWT,
+// and email addresses with WithPIIRedaction.
+func (c *Client) redactString(s string) string {
+	if key := c.lastSecret.Load(); key != nil && *key != "" {
+		s = strings.ReplaceAll(s, *key, redacted)
+	}
+	if key, ok := c.credentials.(StaticCredentials); ok && key != "" {
+		s = strings.ReplaceAll(s, string(key), redacted)
+	}
+	if c.fallbackAPIKey != "" {
+		s = strings.ReplaceAll(s, c.fallbackAPIKey, redacted)
+	}
+	if len(c.signingSecret) > 0 {
+		s = strings.ReplaceAll(s, string(c.signingSecret), redacted)
+	}
+	return c.redactEmails(jwtPattern.ReplaceAllString(s, redacted))
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+type redactingHandler struct {
+	next   slog.Handler
+	client *Client
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, h.client.redactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redactedAttrs[i] = h.redactAttr(a)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redactedAttrs), client: h.client}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), client: h.client}
+}
+
+func (h *redactingHandler) redactAttr(a slog.Attr) slog.Attr {
+	if isSensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		redactedGroup := make([]any, len(group))
+		for i, ga := range group {
+			redactedGroup[i] = h.redactAttr(ga)
+		}
+		return slog.Group(a.Key, redactedGroup...)
+	case slog.KindString:
+		return slog.String(a.Key, h.client.redactString(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, h.client.redactString(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
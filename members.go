@@ -0,0 +1,129 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// MembersService manages who has access to the account's dashboard.
+type MembersService struct {
+	client *Client
+}
+
+type MemberRole string
+
+const (
+	RoleOwner     MemberRole = "owner"
+	RoleAdmin     MemberRole = "admin"
+	RoleDeveloper MemberRole = "developer"
+	RoleViewer    MemberRole = "viewer"
+)
+
+type MemberStatus string
+
+const (
+	// MemberInvited members have not accepted their invitation yet.
+	MemberInvited MemberStatus = "invited"
+	MemberActive  MemberStatus = "active"
+)
+
+type Member struct {
+	ID        string       `json:"id"`
+	Email     string       `json:"email"`
+	Name      string       `json:"name,omitempty"`
+	Role      MemberRole   `json:"role"`
+	Status    MemberStatus `json:"status"`
+	InvitedAt Timestamp    `json:"invited_at"`
+	JoinedAt  *Timestamp   `json:"joined_at,omitempty"`
+}
+
+type InviteMemberRequest struct {
+	Email string     `json:"email"`
+	Role  MemberRole `json:"role"`
+}
+
+// Invite emails an invitation to join the account with the given role. The
+// member is listed as MemberInvited until they accept.
+func (s *MembersService) Invite(ctx context.Context, invite InviteMemberRequest, opts ...CallOption) (*Member, error) {
+	if invite.Email == "" {
+		return nil, &ValidationError{Field: "email", Message: "must not be empty"}
+	}
+	if invite.Role == "" {
+		return nil, &ValidationError{Field: "role", Message: "must not be empty"}
+	}
+
+	body, err := json.Marshal(invite)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/members", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[Member](ctx, s.client, req, "member", opts...)
+}
+
+// List returns every member, including pending invitations.
+func (s *MembersService) List(ctx context.Context, opts ...CallOption) ([]Member, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/members", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]Member](ctx, s.client, req, "data", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+// SetRole changes a member's role.
+func (s *MembersService) SetRole(ctx context.Context, memberID string, role MemberRole, opts ...CallOption) (*Member, error) {
+	if role == "" {
+		return nil, &ValidationError{Field: "role", Message: "must not be empty"}
+	}
+
+	body, err := json.Marshal(struct {
+		Role MemberRole `json:"role"`
+	}{role})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/api/members/%s", s.client.baseURL, url.PathEscape(memberID)), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[Member](ctx, s.client, req, "member", opts...)
+}
+
+// Remove revokes a member's access, or withdraws a pending invitation.
+func (s *MembersService) Remove(ctx context.Context, memberID string, opts ...CallOption) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/members/%s", s.client.baseURL, url.PathEscape(memberID)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// maxTreeHeadAge bounds how old a signed tree head may be, so an
+// intermediary cannot replay a proof from before a token was revoked.
+const maxTreeHeadAge = 5 * time.Minute
+
+const treeHeadSignaturePrefix = "jwtrevoke-tree-head-v1\n"
+
+var ErrInvalidProof = errors.New("jwt-revoke: revocation proof verification failed")
+
+// SignedTreeHead is the API's signed commitment to the set of revocations in
+// effect at Timestamp. The tree follows RFC 6962: leaves are
+// SHA-256(0x00 || jti), sorted by hash, and interior nodes are
+// SHA-256(0x01 || left || right).
+type SignedTreeHead struct {
+	TreeSize  int       `json:"tree_size"`
+	RootHash  []byte    `json:"root_hash"`
+	Timestamp Timestamp `json:"timestamp"`
+	// Signature is an Ed25519 signature by a snapshot signing key over
+	// "jwtrevoke-tree-head-v1\n" followed by the tree size and the Unix time
+	// in seconds as big-endian 64-bit integers and the root hash.
+	Signature []byte `json:"signature"`
+}
+
+// MerkleLeaf is a leaf of the tree with its RFC 6962 audit path.
+type MerkleLeaf struct {
+	Index     int      `json:"index"`
+	Hash      []byte   `json:"hash"`
+	AuditPath [][]byte `json:"audit_path"`
+}
+
+// RevocationProof shows that a jti is or is not in the tree. A revoked jti
+// has its own leaf. A jti that is not revoked falls between two adjacent
+// leaves, or before the first or after the last one.
+type RevocationProof struct {
+	TreeHead SignedTreeHead `json:"tree_head"`
+	Leaves   []MerkleLeaf   `json:"leaves"`
+}
+
+// IsRevokedVerified is IsRevoked with proof: the answer comes with a Merkle
+// proof that is checked against a tree head signed by a key configured with
+// WithSnapshotPublicKey. A tampering or lying intermediary therefore cannot
+// turn a revoked token into a valid one or the other way round. Tree heads
+// older than five minutes are rejected. Verification failures wrap
+// ErrInvalidProof.
+func (s *RevocationsService) IsRevokedVerified(ctx context.Context, jwtID string, opts ...CallOption) (bool, *RevocationProof, error) {
+	if err := validateJwtID("jwtID", jwtID); err != nil {
+		return false, nil, err
+	}
+	if len(s.client.snapshotKeys) == 0 {
+		return false, nil, errors.New("jwt-revoke: IsRevokedVerified requires WithSnapshotPublicKey")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/revocations/proofs/%s", s.client.baseURL, url.PathEscape(jwtID)), nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return false, nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Revoked bool            `json:"revoked"`
+		Proof   RevocationProof `json:"proof"`
+	}
+	if err := s.client.decodeJSON(resp.Body, &result); err != nil {
+		return false, nil, err
+	}
+
+	revoked, err := result.Proof.verify(jwtID, s.client.clock.Now(), s.client.snapshotKeys)
+	if err != nil {
+		return false, nil, err
+	}
+	if revoked != result.Revoked {
+		return false, nil, fmt.Errorf("%w: answer contradicts the proof", ErrInvalidProof)
+	}
+	return revoked, &result.Proof, nil
+}
+
+// Verify checks the proof for jwtID against keys and reports whether it
+// shows jwtID as revoked.
+func (p *RevocationProof) Verify(jwtID string, keys ...ed25519.PublicKey) (bool, error) {
+	return p.verify(jwtID, time.Now(), keys)
+}
+
+// verify checks the tree head's age against now, the client's clock when
+// called from IsRevokedVerified.
+func (p *RevocationProof) verify(jwtID string, now time.Time, keys []ed25519.PublicKey) (bool, error) {
+	head := p.TreeHead
+	if !head.verify(keys) {
+		return false, fmt.Errorf("%w: tree head signature is invalid", ErrInvalidProof)
+	}
+	if now.Sub(head.Timestamp.Time) > maxTreeHeadAge {
+		return false, fmt.Errorf("%w: tree head from %s is too old", ErrInvalidProof, head.Timestamp.Format(time.RFC3339))
+	}
+	for _, leaf := range p.Leaves {
+		if !verifyInclusion(leaf, head.TreeSize, head.RootHash) {
+			return false, fmt.Errorf("%w: audit path for leaf %d does not match the root", ErrInvalidProof, leaf.Index)
+		}
+	}
+
+	key := merkleLeafHash(jwtID)
+	last := head.TreeSize - 1
+	switch len(p.Leaves) {
+	case 0:
+		if head.TreeSize == 0 && bytes.Equal(head.RootHash, emptyTreeHash()) {
+			return false, nil
+		}
+	case 1:
+		leaf := p.Leaves[0]
+		switch cmp := bytes.Compare(key, leaf.Hash); {
+		case cmp == 0:
+			return true, nil
+		case cmp < 0 && leaf.Index == 0, cmp > 0 && leaf.Index == last:
+			return false, nil
+		}
+	case 2:
+		left, right := p.Leaves[0], p.Leaves[1]
+		if right.Index == left.Index+1 && bytes.Compare(left.Hash, key) < 0 && bytes.Compare(key, right.Hash) < 0 {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("%w: leaves do not prove or disprove the token", ErrInvalidProof)
+}
+
+func (h SignedTreeHead) verify(keys []ed25519.PublicKey) bool {
+	if h.TreeSize < 0 || len(h.RootHash) != sha256.Size {
+		return false
+	}
+	msg := SignedTreeHeadMessage(h.TreeSize, h.Timestamp.Time, h.RootHash)
+	for _, key := range keys {
+		if ed25519.Verify(key, msg, h.Signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedTreeHeadMessage returns the bytes a tree head signature covers.
+func SignedTreeHeadMessage(treeSize int, timestamp time.Time, rootHash []byte) []byte {
+	msg := []byte(treeHeadSignaturePrefix)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(treeSize))
+	msg = binary.BigEndian.AppendUint64(msg, uint64(timestamp.Unix()))
+	return append(msg, rootHash...)
+}
+
+// verifyInclusion follows RFC 9162, section 2.1.3.2.
+func verifyInclusion(leaf MerkleLeaf, treeSize int, root []byte) bool {
+	if leaf.Index < 0 || leaf.Index >= treeSize || len(leaf.Hash) != sha256.Size {
+		return false
+	}
+	fn, sn := leaf.Index, treeSize-1
+	r := leaf.Hash
+	for _, p := range leaf.AuditPath {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+func merkleLeafHash(jwtID string) []byte {
+	h := sha256.New()
+	h.Write([]byte{0})
+	h.Write([]byte(jwtID))
+	return h.Sum(nil)
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{1})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+func emptyTreeHash() []byte {
+	sum := sha256.Sum256(nil)
+	return sum[:]
+}
@@ -0,0 +1,92 @@
+package jwtrevokeapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Limits on metadata attached to a revocation.
+const (
+	MaxMetadataKeys        = 20
+	MaxMetadataKeyLength   = 64
+	MaxMetadataValueLength = 512
+)
+
+// Metadata holds arbitrary labels on a revocation, such as a ticket ID,
+// incident number, or tenant.
+type Metadata map[string]string
+
+// labels returns m as sorted key=value pairs, the form list queries use, so
+// the same filter always produces the same URL.
+func (m Metadata) labels() []string {
+	labels := make([]string, 0, len(m))
+	for k, v := range m {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// ParseLabel splits a key=value label as used in list filters.
+func ParseLabel(label string) (key, value string, err error) {
+	key, value, ok := strings.Cut(label, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("jwt-revoke: label %q is not key=value", label)
+	}
+	return key, value, nil
+}
+
+// Matches reports whether m has every key in labels set to the same value.
+func (m Metadata) Matches(labels map[string]string) bool {
+	for k, v := range labels {
+		if got, ok := m[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// encode flattens m for a single CSV column.
+func (m Metadata) encode() string {
+	v := url.Values{}
+	for k, val := range m {
+		v.Set(k, val)
+	}
+	return v.Encode()
+}
+
+func decodeMetadata(s string) (Metadata, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := url.ParseQuery(s)
+	if err != nil {
+		return nil, fmt.Errorf("metadata: %w", err)
+	}
+	m := make(Metadata, len(v))
+	for k := range v {
+		m[k] = v.Get(k)
+	}
+	return m, nil
+}
+
+func (m Metadata) validate() *ValidationError {
+	if len(m) > MaxMetadataKeys {
+		return &ValidationError{Field: "metadata", Message: fmt.Sprintf("must have at most %d keys", MaxMetadataKeys)}
+	}
+	for k, v := range m {
+		switch {
+		case k == "":
+			return &ValidationError{Field: "metadata", Message: "keys must not be empty"}
+		case len(k) > MaxMetadataKeyLength:
+			return &ValidationError{Field: "metadata", Message: fmt.Sprintf("key %q is longer than %d bytes", k, MaxMetadataKeyLength)}
+		case strings.Contains(k, "="):
+			return &ValidationError{Field: "metadata", Message: fmt.Sprintf("key %q must not contain =", k)}
+		case len(v) > MaxMetadataValueLength:
+			return &ValidationError{Field: "metadata", Message: fmt.Sprintf("value for %q is longer than %d bytes", k, MaxMetadataValueLength)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,368 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ErrTokenRevoked, ErrRequestDenied and ErrInvalidToken are passed to
+// MiddlewareOptions.ErrorHandler for revoked tokens, requests Decide denies,
+// and tokens MiddlewareOptions.KeySet rejects.
+var (
+	ErrTokenRevoked  = errors.New("jwt-revoke: token has been revoked")
+	ErrRequestDenied = errors.New("jwt-revoke: request denied by policy")
+	ErrInvalidToken  = errors.New("jwt-revoke: invalid token")
+)
+
+// RevocationChecker is satisfied by *Cache and *Mirror.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jwtID string) (bool, error)
+}
+
+type MiddlewareOptions struct {
+	// KeySet, when set, verifies the signature of every request's token
+	// before anything is read from it. Requests whose token fails are
+	// rejected with a 401, or passed to ErrorHandler, and the claims of the
+	// others are available through ClaimsFromContext. Without it, claims are
+	// only available unverified, through UnverifiedClaimsFromContext.
+	KeySet KeySet
+	// TokenSources are tried in order to find the request's token, whose
+	// claims the default JwtID and SessionID read and handlers receive. It
+	// defaults to the bearer token in the Authorization header.
+	TokenSources []TokenSource
+	// JwtID extracts the token ID from a request. It defaults to the jti
+	// claim of the token. Requests without one are passed through;
+	// authenticating them is left to the application.
+	JwtID func(r *http.Request) (string, bool)
+	// SessionID extracts the session ID, checked in addition to the token ID
+	// when the checker implements SessionChecker. It defaults to the sid
+	// claim of the token.
+	SessionID func(r *http.Request) (string, bool)
+	// SkipPaths are passed through unchecked, e.g. health checks. A path
+	// ending in a slash skips everything below it. Request paths are
+	// cleaned of dot segments and repeated slashes before matching.
+	SkipPaths []string
+	// SkipMethods are passed through unchecked, e.g. OPTIONS for CORS
+	// preflight requests.
+	SkipMethods []string
+	// Skip, when set, passes through the requests it reports true for.
+	Skip func(r *http.Request) bool
+	// ErrorHandler writes every rejection in one place, for gateways that
+	// expect their own error shape. err is ErrTokenRevoked,
+	// ErrRequestDenied, or why the status could not be determined.
+	// OnRevoked, OnError, and OnDenied take precedence when set.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	// OnRevoked writes the response for revoked tokens. Defaults to a 401
+	// with the API's error shape.
+	OnRevoked http.Handler
+	// OnError writes the response when the status could not be determined,
+	// e.g. when a Cache fails closed, or when Decide fails. Defaults to a 503.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+	// Decide, when set, makes the final decision for every request from the
+	// revocation check's outcome, e.g. by asking a policy engine such as
+	// OPA; see OPADecision. The request it receives carries the token's
+	// claims.
+	Decide DecisionFunc
+	// OnDenied writes the response for requests Decide denies whose token is
+	// not revoked. Defaults to a 403.
+	OnDenied http.Handler
+	// Allowlist switches the middleware to deny by default: only tokens
+	// whose jti it allows are let through, and requests without a jti are
+	// rejected. Other tokens are answered like revoked ones. The checker
+	// passed to Middleware may then be nil; if not, it still applies.
+	Allowlist AllowChecker
+}
+
+// Middleware rejects requests carrying a revoked token, or one from a revoked
+// session when the checker implements SessionChecker. Pair it with a Cache
+// to control caching and the fail-open or fail-closed policy, or with a
+// Mirror to check against a local copy of the list. Requests it lets through
+// carry the bearer token's claims; see ClaimsFromContext and
+// MiddlewareOptions.KeySet.
+func Middleware(checker RevocationChecker, opts MiddlewareOptions) func(http.Handler) http.Handler {
+	sessions, _ := checker.(SessionChecker)
+	opts.setDefaults()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var revoked bool
+			var err error
+			// The token is parsed once for the default extractors and the
+			// claims handed to next.
+			token, hasToken := requestToken(r, opts.TokenSources)
+			claims := parsedClaims(token)
+			verified := false
+			if opts.KeySet != nil && hasToken {
+				payload, verr := opts.KeySet.VerifySignature(r.Context(), token)
+				if verr == nil {
+					claims, verr = claimsFromPayload(payload)
+				}
+				if verr != nil {
+					opts.onInvalidToken(w, r, verr)
+					return
+				}
+				verified = true
+			}
+			jwtID, hasJwtID := requestJwtID(r, token, claims, opts.JwtID)
+			if hasJwtID && checker != nil {
+				revoked, err = checker.IsRevoked(r.Context(), jwtID)
+			}
+			sid, hasSID := requestSessionID(r, token, claims, opts.SessionID)
+			if hasSID && sessions != nil && !revoked && err == nil {
+				revoked, err = sessions.IsSessionRevoked(r.Context(), sid)
+			}
+			if opts.Allowlist != nil && !revoked && err == nil {
+				allowed := false
+				if hasJwtID {
+					allowed, err = opts.Allowlist.IsAllowed(r.Context(), jwtID)
+				}
+				revoked = !allowed && err == nil
+			}
+			if opts.Decide != nil {
+				allow, derr := opts.Decide(withClaims(r, claims, verified), Decision{JwtID: jwtID, SessionID: sid, Revoked: revoked, Err: err})
+				switch {
+				case derr != nil:
+					opts.OnError(w, r, derr)
+				case allow:
+					next.ServeHTTP(w, withClaims(r, claims, verified))
+				case revoked:
+					opts.OnRevoked.ServeHTTP(w, r)
+				default:
+					opts.OnDenied.ServeHTTP(w, r)
+				}
+				return
+			}
+			if err != nil {
+				opts.OnError(w, r, err)
+				return
+			}
+			if revoked {
+				opts.OnRevoked.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, withClaims(r, claims, verified))
+		})
+	}
+}
+
+func (o *MiddlewareOptions) setDefaults() {
+	if o.OnRevoked == nil {
+		o.OnRevoked = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if o.ErrorHandler != nil {
+				o.ErrorHandler(w, r, ErrTokenRevoked)
+				return
+			}
+			writeMiddlewareError(w, http.StatusUnauthorized, "token has been revoked")
+		})
+	}
+	if o.OnError == nil {
+		o.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+			if o.ErrorHandler != nil {
+				o.ErrorHandler(w, r, err)
+				return
+			}
+			writeMiddlewareError(w, http.StatusServiceUnavailable, "revocation status unavailable")
+		}
+	}
+	if o.OnDenied == nil {
+		o.OnDenied = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if o.ErrorHandler != nil {
+				o.ErrorHandler(w, r, ErrRequestDenied)
+				return
+			}
+			writeMiddlewareError(w, http.StatusForbidden, "request denied by policy")
+		})
+	}
+}
+
+func (o *MiddlewareOptions) onInvalidToken(w http.ResponseWriter, r *http.Request, err error) {
+	if o.ErrorHandler != nil {
+		o.ErrorHandler(w, r, fmt.Errorf("%w: %v", ErrInvalidToken, err))
+		return
+	}
+	writeMiddlewareError(w, http.StatusUnauthorized, "invalid token")
+}
+
+func (o *MiddlewareOptions) skip(r *http.Request) bool {
+	for _, method := range o.SkipMethods {
+		if r.Method == method {
+			return true
+		}
+	}
+	// Match the path as the router will resolve it, so dot segments such
+	// as /health/../admin cannot borrow a skipped prefix.
+	requestPath := path.Clean("/" + r.URL.Path)
+	if strings.HasSuffix(r.URL.Path, "/") && requestPath != "/" {
+		requestPath += "/"
+	}
+	for _, skipped := range o.SkipPaths {
+		if requestPath == skipped || strings.HasSuffix(skipped, "/") && strings.HasPrefix(requestPath, skipped) {
+			return true
+		}
+	}
+	return o.Skip != nil && o.Skip(r)
+}
+
+func requestJwtID(r *http.Request, token string, claims *Claims, extract func(*http.Request) (string, bool)) (string, bool) {
+	switch {
+	case extract != nil:
+		return extract(r)
+	case claims != nil:
+		return claims.JwtID, claims.JwtID != ""
+	default:
+		// Claims that fail to parse as a whole may still carry a jti.
+		jwtID, err := JwtIDFromToken(token)
+		return jwtID, err == nil && jwtID != ""
+	}
+}
+
+func requestSessionID(r *http.Request, token string, claims *Claims, extract func(*http.Request) (string, bool)) (string, bool) {
+	switch {
+	case extract != nil:
+		return extract(r)
+	case claims != nil:
+		return claims.SessionID, claims.SessionID != ""
+	default:
+		return sessionIDFromToken(token)
+	}
+}
+
+// BearerJwtID returns the jti claim of the bearer token in the Authorization
+// header. The token's signature is not verified.
+func BearerJwtID(r *http.Request) (string, bool) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return "", false
+	}
+	jwtID, err := JwtIDFromToken(token)
+	return jwtID, err == nil && jwtID != ""
+}
+
+// BearerSessionID returns the sid claim of the bearer token in the
+// Authorization header. The token's signature is not verified.
+func BearerSessionID(r *http.Request) (string, bool) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return "", false
+	}
+	return sessionIDFromToken(token)
+}
+
+func sessionIDFromToken(token string) (string, bool) {
+	payload, err := tokenPayload(token)
+	if err != nil {
+		return "", false
+	}
+	var claims struct {
+		SessionID string `json:"sid"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", false
+	}
+	return claims.SessionID, claims.SessionID != ""
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	return token, ok && strings.EqualFold(scheme, "Bearer")
+}
+
+// JwtIDFromToken extracts the jti claim from a compact JWT without verifying
+// its signature.
+func JwtIDFromToken(token string) (string, error) {
+	payload, err := tokenPayload(token)
+	if err != nil {
+		return "", err
+	}
+	var claims struct {
+		JwtID string `json:"jti"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", err
+	}
+	return claims.JwtID, nil
+}
+
+// tokenClaims are the registered claims the SDK reads from a JWT.
+type tokenClaims struct {
+	JwtID     string     `json:"jti"`
+	Subject   string     `json:"sub"`
+	Issuer    string     `json:"iss"`
+	Audience  audience   `json:"aud"`
+	ExpiresAt *Timestamp `json:"exp"`
+	IssuedAt  *Timestamp `json:"iat"`
+	NotBefore *Timestamp `json:"nbf"`
+	Scope     string     `json:"scope"`
+	ClientID  string     `json:"client_id"`
+	Username  string     `json:"username"`
+}
+
+// audience is the aud claim, which may be a single string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		if single != "" {
+			*a = audience{single}
+		}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// parseClaims decodes the payload of a compact JWT without verifying its
+// signature.
+func parseClaims(token string) (*tokenClaims, error) {
+	payload, err := tokenPayload(token)
+	if err != nil {
+		return nil, err
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// tokenKeyID returns the kid of token's header, or "" when it has none or
+// the header cannot be decoded.
+func tokenKeyID(token string) string {
+	header, _, _ := strings.Cut(strings.TrimSpace(token), ".")
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(header, "="))
+	if err != nil {
+		return ""
+	}
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	if json.Unmarshal(data, &h) != nil {
+		return ""
+	}
+	return h.Kid
+}
+
+func tokenPayload(token string) ([]byte, error) {
+	_, rest, ok := strings.Cut(strings.TrimSpace(token), ".")
+	payload, signature, ok2 := strings.Cut(rest, ".")
+	if !ok || !ok2 || strings.Contains(signature, ".") {
+		return nil, errors.New("jwt-revoke: malformed JWT")
+	}
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+}
+
+func writeMiddlewareError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"message": message, "data": nil})
+}
@@ -0,0 +1,664 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrMirrorNotReady = errors.New("jwt-revoke: mirror has not completed an initial sync")
+
+// ErrMirrorStale is returned by Mirror.Healthy when the last successful sync
+// is older than MirrorOptions.MaxStaleness.
+var ErrMirrorStale = errors.New("jwt-revoke: mirror is stale")
+
+const (
+	defaultMirrorSyncInterval     = 30 * time.Second
+	defaultMirrorFullSyncInterval = time.Hour
+	// deltas overlap the previous sync window so events are never missed
+	// because of clock skew between the client and the server.
+	mirrorSyncOverlap = time.Minute
+
+	// A persisted list is written under a new generation's prefix and
+	// switched to by the single mirrorStateKey write, so a crash or failure
+	// midway leaves the previous list intact. Generation 0 is the layout of
+	// earlier versions, with the cursor in mirrorSinceKey and
+	// mirrorLastSyncKey.
+	mirrorTokenPrefix = "revocations/"
+	mirrorStateKey    = "meta/state"
+	mirrorSinceKey    = "meta/since"
+	mirrorLastSyncKey = "meta/last_sync"
+	mirrorLeaderKey   = "meta/leader"
+)
+
+// mirrorState is the persisted list's generation and sync cursor.
+type mirrorState struct {
+	Generation int64     `json:"generation"`
+	Since      time.Time `json:"since"`
+	LastSync   time.Time `json:"last_sync"`
+}
+
+func mirrorGenerationPrefix(generation int64) string {
+	if generation == 0 {
+		return mirrorTokenPrefix
+	}
+	return fmt.Sprintf("revocations@%d/", generation)
+}
+
+// Mirror.loop states.
+const (
+	mirrorIdle int32 = iota
+	mirrorSyncing
+	mirrorEnded
+)
+
+type MirrorOptions struct {
+	// SyncInterval is how often deltas are fetched. Defaults to 30s.
+	SyncInterval time.Duration
+	// FullSyncInterval is how often the full list is re-downloaded to
+	// correct any drift. Defaults to 1h.
+	FullSyncInterval time.Duration
+	// MaxStaleness triggers OnStale, and a warning log, when the last
+	// successful sync is older than this. Zero disables the alarm.
+	MaxStaleness time.Duration
+	OnStale      func(age time.Duration, lastErr error)
+	// Store persists the mirror so that after a restart it serves the last
+	// known list immediately and catches up with a delta sync.
+	Store Store
+}
+
+// Mirror keeps a complete local copy of the revocation list and answers
+// IsRevoked without network calls.
+type Mirror struct {
+	client *Client
+	opts   MirrorOptions
+
+	mu       sync.RWMutex
+	tokens   map[string]RevokedToken
+	since    time.Time
+	lastSync time.Time
+	lastErr  error
+	ready    bool
+	leader   bool
+	// pages remembers the last full sync page by page, so unchanged pages
+	// can be revalidated with a conditional request instead of downloaded.
+	pages []mirrorPage
+	// loop is the state of the background sync started by Start.
+	loop atomic.Int32
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewMirror(client *Client, opts MirrorOptions) *Mirror {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = defaultMirrorSyncInterval
+	}
+	if opts.FullSyncInterval <= 0 {
+		opts.FullSyncInterval = defaultMirrorFullSyncInterval
+	}
+	return &Mirror{
+		client: client,
+		opts:   opts,
+		tokens: make(map[string]RevokedToken),
+	}
+}
+
+// Start performs an initial sync and then keeps the mirror up to date in the
+// background until ctx is cancelled or Stop is called. With a Store holding a
+// previous snapshot, Start only fails if neither the snapshot nor the API is
+// usable.
+func (m *Mirror) Start(ctx context.Context) error {
+	loaded, err := m.load(ctx)
+	if err != nil {
+		m.client.log(ctx, slog.LevelWarn, "jwtrevoke: failed to load persisted mirror", "error", err)
+	}
+	if loaded {
+		if err := m.DeltaSync(ctx); err != nil {
+			m.client.log(ctx, slog.LevelWarn, "jwtrevoke: serving persisted mirror after failed catch-up sync", "error", err)
+		}
+	} else if err := m.FullSync(ctx); err != nil {
+		return err
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	ctx, cancel := context.WithCancel(ctx)
+	stopOnClose := context.AfterFunc(m.client.lifecycle.ctx, cancel)
+	done := m.done
+	unregister := m.client.lifecycle.register(false, func(ctx context.Context) error {
+		return waitDone(ctx, done)
+	})
+	m.loop.Store(mirrorSyncing)
+	go func() {
+		defer unregister()
+		defer stopOnClose()
+		defer cancel()
+		defer m.loop.Store(mirrorEnded)
+		m.run(ctx)
+	}()
+	return nil
+}
+
+// Stop ends background syncing. Client.Close stops the client's mirrors.
+func (m *Mirror) Stop() {
+	if m.stop == nil {
+		return
+	}
+	close(m.stop)
+	<-m.done
+	m.stop = nil
+}
+
+func (m *Mirror) run(ctx context.Context) {
+	defer close(m.done)
+	defer m.releaseLeadership()
+
+	clock := m.client.clock
+	lastFull := clock.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stop:
+			return
+		case <-clock.After(m.opts.SyncInterval):
+		}
+
+		if !m.acquireLeadership(ctx) {
+			if err := m.reloadIfNewer(ctx); err != nil {
+				m.client.log(ctx, slog.LevelWarn, "jwtrevoke: mirror reload from shared store failed", "error", err)
+			}
+			m.checkStaleness(ctx)
+			continue
+		}
+
+		var err error
+		if clock.Now().Sub(lastFull) >= m.opts.FullSyncInterval {
+			if err = m.FullSync(ctx); err == nil {
+				lastFull = clock.Now()
+			}
+		} else {
+			err = m.DeltaSync(ctx)
+		}
+		if err != nil {
+			m.client.log(ctx, slog.LevelWarn, "jwtrevoke: mirror sync failed", "error", err)
+		}
+		if pruned := m.Prune(); pruned > 0 {
+			m.client.log(ctx, slog.LevelDebug, "jwtrevoke: pruned expired revocations from mirror", "pruned", pruned)
+		}
+		m.checkStaleness(ctx)
+	}
+}
+
+// releaseLeadership gives up the shared store's leader lock, if held, so
+// another instance can take over syncing right away.
+func (m *Mirror) releaseLeadership() {
+	if locker, ok := m.opts.Store.(Locker); ok && m.leader {
+		locker.Unlock(context.Background(), mirrorLeaderKey)
+		m.leader = false
+	}
+}
+
+// Prune drops revocations whose expiry date has passed and returns how many
+// it dropped. A running mirror prunes after every sync. Persisted entries
+// expire from the Store on their own.
+func (m *Mirror) Prune() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.client.clock.Now()
+	pruned := 0
+	for jwtID, t := range m.tokens {
+		if t.expiredAt(now) {
+			delete(m.tokens, jwtID)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+type mirrorPage struct {
+	cursor string
+	etag   string
+	next   string
+	jwtIDs []string
+}
+
+// FullSync replaces the mirror's contents with the complete revocation list.
+// Pages that have not changed since the previous full sync are revalidated
+// with If-None-Match rather than downloaded again.
+func (m *Mirror) FullSync(ctx context.Context) error {
+	ctx = ContextWithBulkPriority(ctx)
+	started := time.Now()
+	m.mu.RLock()
+	previous, old := m.pages, m.tokens
+	m.mu.RUnlock()
+
+	fresh := make(map[string]RevokedToken, len(old))
+	var pages []mirrorPage
+	unchanged := 0
+	var params ListOptions
+	for i := 0; ; i++ {
+		// Pages served from the HTTP cache could predate the sync window.
+		opts := []CallOption{WithCallNoCache()}
+		if i < len(previous) && previous[i].cursor == params.Cursor && previous[i].etag != "" {
+			opts = append(opts, IfNoneMatch(previous[i].etag))
+		}
+
+		var p mirrorPage
+		page, err := m.client.Revocations.ListPage(ctx, params, opts...)
+		switch {
+		case errors.Is(err, ErrNotModified):
+			p = previous[i]
+			for _, jwtID := range p.jwtIDs {
+				if t, ok := old[jwtID]; ok {
+					fresh[jwtID] = t
+				}
+			}
+			unchanged++
+		case err != nil:
+			m.recordError(err)
+			return err
+		default:
+			p = mirrorPage{cursor: params.Cursor, etag: page.ETag, next: page.NextCursor}
+			for _, t := range page.Tokens {
+				fresh[t.JwtID] = t
+				p.jwtIDs = append(p.jwtIDs, t.JwtID)
+			}
+		}
+		pages = append(pages, p)
+		if p.next == "" {
+			break
+		}
+		params.Cursor = p.next
+	}
+
+	m.mu.Lock()
+	m.tokens = fresh
+	m.pages = pages
+	m.since = started.Add(-mirrorSyncOverlap)
+	m.lastSync = m.client.clock.Now()
+	m.lastErr = nil
+	m.ready = true
+	since, lastSync := m.since, m.lastSync
+	m.client.stats.recordSnapshot(lastSync)
+	m.mu.Unlock()
+
+	m.persistFull(ctx, fresh, since, lastSync)
+
+	m.client.log(ctx, slog.LevelDebug, "jwtrevoke: mirror full sync complete",
+		"revocations", len(fresh), "pages", len(pages), "unchanged_pages", unchanged)
+	return nil
+}
+
+// DeltaSync applies the changes that happened since the previous sync.
+func (m *Mirror) DeltaSync(ctx context.Context) error {
+	ctx = ContextWithBulkPriority(ctx)
+	m.mu.RLock()
+	since, ready := m.since, m.ready
+	m.mu.RUnlock()
+	if !ready {
+		return m.FullSync(ctx)
+	}
+
+	from := since
+	changes, err := m.client.Revocations.Changes(ctx, since, WithCallNoCache())
+	if err != nil {
+		m.recordError(err)
+		return err
+	}
+
+	m.mu.Lock()
+	for _, ev := range changes.Events {
+		m.applyLocked(ev)
+	}
+	if !changes.ServerTime.IsZero() {
+		m.since = changes.ServerTime.Add(-mirrorSyncOverlap)
+	}
+	m.lastSync = m.client.clock.Now()
+	m.lastErr = nil
+	since, lastSync := m.since, m.lastSync
+	m.client.stats.recordSnapshot(lastSync)
+	m.mu.Unlock()
+
+	m.persistEvents(ctx, changes.Events, since, lastSync)
+	m.client.events.publish(changes.Events)
+	if !changes.ServerTime.IsZero() {
+		m.client.events.covered(from, changes.ServerTime.Time)
+	}
+
+	m.client.log(ctx, slog.LevelDebug, "jwtrevoke: mirror delta sync complete", "changes", len(changes.Events))
+	return nil
+}
+
+// acquireLeadership reports whether this mirror should sync from the API. It
+// is always true unless the Store is a Locker held by another process.
+func (m *Mirror) acquireLeadership(ctx context.Context) bool {
+	locker, ok := m.opts.Store.(Locker)
+	if !ok {
+		return true
+	}
+	leader, err := locker.TryLock(ctx, mirrorLeaderKey, 3*m.opts.SyncInterval)
+	if err != nil {
+		// Syncing redundantly is safer than letting every instance go stale.
+		m.client.log(ctx, slog.LevelWarn, "jwtrevoke: mirror leader election failed", "error", err)
+		return true
+	}
+	if leader != m.leader {
+		m.client.log(ctx, slog.LevelInfo, "jwtrevoke: mirror leadership changed", "leader", leader)
+	}
+	m.leader = leader
+	return leader
+}
+
+func (m *Mirror) reloadIfNewer(ctx context.Context) error {
+	state, ok, err := loadMirrorState(ctx, m.opts.Store)
+	if err != nil || !ok {
+		return err
+	}
+	if !state.LastSync.After(m.LastSync()) {
+		return nil
+	}
+	_, err = m.load(ctx)
+	return err
+}
+
+func (m *Mirror) applyLocked(ev RevocationEvent) {
+	switch ev.Type {
+	case EventDeleted, EventExpired:
+		delete(m.tokens, ev.Token.JwtID)
+	default:
+		m.tokens[ev.Token.JwtID] = ev.Token
+	}
+}
+
+func (m *Mirror) load(ctx context.Context) (bool, error) {
+	if m.opts.Store == nil {
+		return false, nil
+	}
+	tokens, since, lastSync, ok, err := loadSnapshot(ctx, m.opts.Store)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	m.mu.Lock()
+	m.tokens = tokens
+	m.since = since
+	m.lastSync = lastSync
+	m.ready = true
+	m.mu.Unlock()
+	m.client.stats.recordSnapshot(lastSync)
+
+	m.client.log(ctx, slog.LevelInfo, "jwtrevoke: loaded persisted mirror", "revocations", len(tokens), "last_sync", lastSync)
+	return true, nil
+}
+
+func (m *Mirror) persistFull(ctx context.Context, tokens map[string]RevokedToken, since, lastSync time.Time) {
+	if m.opts.Store == nil {
+		return
+	}
+	if err := persistSnapshot(ctx, m.opts.Store, tokens, since, lastSync, m.client.clock.Now(), m.client.minimizeToken); err != nil {
+		m.client.log(ctx, slog.LevelWarn, "jwtrevoke: failed to persist mirror", "error", err)
+	}
+}
+
+func (m *Mirror) persistEvents(ctx context.Context, events []RevocationEvent, since, lastSync time.Time) {
+	store := m.opts.Store
+	if store == nil {
+		return
+	}
+
+	state, _, err := loadMirrorState(ctx, store)
+	prefix := mirrorGenerationPrefix(state.Generation)
+	for _, ev := range events {
+		if err != nil {
+			break
+		}
+		if ev.Type == EventDeleted || ev.Type == EventExpired {
+			err = store.Delete(ctx, prefix+ev.Token.JwtID)
+		} else {
+			err = persistToken(ctx, store, prefix, ev.Token.JwtID, m.client.minimizeToken(ev.Token), m.client.clock.Now())
+		}
+	}
+	// Only advance the stored cursor once every event is durable.
+	if err == nil {
+		err = persistState(ctx, store, mirrorState{Generation: state.Generation, Since: since, LastSync: lastSync})
+	}
+	if err != nil {
+		m.client.log(ctx, slog.LevelWarn, "jwtrevoke: failed to persist mirror changes", "error", err)
+	}
+}
+
+// loadSnapshot reads a revocation list persisted by persistSnapshot. ok is
+// false when store holds none. A list replaced while it is being read is
+// read again.
+func loadSnapshot(ctx context.Context, store Store) (tokens map[string]RevokedToken, since, lastSync time.Time, ok bool, err error) {
+	for attempt := 0; ; attempt++ {
+		state, ok, err := loadMirrorState(ctx, store)
+		if err != nil || !ok {
+			return nil, time.Time{}, time.Time{}, false, err
+		}
+		prefix := mirrorGenerationPrefix(state.Generation)
+		tokens = make(map[string]RevokedToken)
+		err = store.Scan(ctx, prefix, func(key string, value []byte) error {
+			var t RevokedToken
+			if err := json.Unmarshal(value, &t); err != nil {
+				return err
+			}
+			tokens[strings.TrimPrefix(key, prefix)] = t
+			return nil
+		})
+		if err != nil {
+			return nil, time.Time{}, time.Time{}, false, err
+		}
+		current, _, err := loadMirrorState(ctx, store)
+		if err != nil {
+			return nil, time.Time{}, time.Time{}, false, err
+		}
+		if current.Generation == state.Generation || attempt == 2 {
+			return tokens, state.Since, state.LastSync, true, nil
+		}
+	}
+}
+
+// loadMirrorState reads the persisted generation and cursor, falling back to
+// the keys of generation 0. ok is false when store holds no list.
+func loadMirrorState(ctx context.Context, store Store) (state mirrorState, ok bool, err error) {
+	raw, ok, err := store.Get(ctx, mirrorStateKey)
+	if err != nil {
+		return mirrorState{}, false, err
+	}
+	if ok {
+		err = json.Unmarshal(raw, &state)
+		return state, err == nil, err
+	}
+
+	rawSince, ok, err := store.Get(ctx, mirrorSinceKey)
+	if err != nil || !ok {
+		return mirrorState{}, false, err
+	}
+	if state.Since, err = time.Parse(time.RFC3339Nano, string(rawSince)); err != nil {
+		return mirrorState{}, false, err
+	}
+	if raw, ok, err := store.Get(ctx, mirrorLastSyncKey); err == nil && ok {
+		state.LastSync, _ = time.Parse(time.RFC3339Nano, string(raw))
+	}
+	return state, true, nil
+}
+
+// persistSnapshot replaces the revocation list in store with tokens, passed
+// through minimize if it is set, with TTLs counted from now. The tokens are written as a new generation
+// that takes over in one write once complete; the previous generation is
+// deleted afterwards.
+func persistSnapshot(ctx context.Context, store Store, tokens map[string]RevokedToken, since, lastSync, now time.Time, minimize func(RevokedToken) RevokedToken) error {
+	previous, _, err := loadMirrorState(ctx, store)
+	if err != nil {
+		return err
+	}
+	next := mirrorState{Generation: previous.Generation + 1, Since: since, LastSync: lastSync}
+	prefix := mirrorGenerationPrefix(next.Generation)
+
+	// Leftovers of an earlier attempt at this generation that failed.
+	if err := deletePrefix(ctx, store, prefix); err != nil {
+		return err
+	}
+	for jwtID, t := range tokens {
+		if minimize != nil {
+			t = minimize(t)
+		}
+		if err := persistToken(ctx, store, prefix, jwtID, t, now); err != nil {
+			return err
+		}
+	}
+	if err := persistState(ctx, store, next); err != nil {
+		return err
+	}
+
+	// The new list is in place; failing to clean up the old one only
+	// leaves unused keys behind.
+	deletePrefix(ctx, store, mirrorGenerationPrefix(previous.Generation))
+	if previous.Generation == 0 {
+		store.Delete(ctx, mirrorSinceKey)
+		store.Delete(ctx, mirrorLastSyncKey)
+	}
+	return nil
+}
+
+func deletePrefix(ctx context.Context, store Store, prefix string) error {
+	var keys []string
+	if err := store.Scan(ctx, prefix, func(key string, _ []byte) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func persistToken(ctx context.Context, store Store, prefix, jwtID string, t RevokedToken, now time.Time) error {
+	value, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	// Entries expire with the token itself, so stale denylist rows never
+	// outlive the tokens they block.
+	var ttl time.Duration
+	if !t.Permanent() {
+		ttl = t.ExpiryDate.Time.Sub(now)
+		if ttl <= 0 {
+			return store.Delete(ctx, prefix+jwtID)
+		}
+	}
+	return store.Set(ctx, prefix+jwtID, value, ttl)
+}
+
+func persistState(ctx context.Context, store Store, state mirrorState) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return store.Set(ctx, mirrorStateKey, value, 0)
+}
+
+func (m *Mirror) recordError(err error) {
+	m.mu.Lock()
+	m.lastErr = err
+	m.mu.Unlock()
+}
+
+func (m *Mirror) checkStaleness(ctx context.Context) {
+	if m.opts.MaxStaleness <= 0 {
+		return
+	}
+	age := m.Staleness()
+	if age <= m.opts.MaxStaleness {
+		return
+	}
+
+	m.mu.RLock()
+	lastErr := m.lastErr
+	m.mu.RUnlock()
+
+	m.client.log(ctx, slog.LevelWarn, "jwtrevoke: mirror is stale", "age", age, "error", lastErr)
+	if m.opts.OnStale != nil {
+		m.opts.OnStale(age, lastErr)
+	}
+}
+
+// IsRevoked reports whether jwtID is revoked according to the local copy. It
+// returns ErrMirrorNotReady until the first sync has completed.
+func (m *Mirror) IsRevoked(ctx context.Context, jwtID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.ready {
+		return false, ErrMirrorNotReady
+	}
+	t, ok := m.tokens[jwtID]
+	return ok && !t.pendingAt(m.client.clock.Now()), nil
+}
+
+func (m *Mirror) Get(jwtID string) (RevokedToken, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tokens[jwtID]
+	return t, ok
+}
+
+func (m *Mirror) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.tokens)
+}
+
+func (m *Mirror) LastSync() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastSync
+}
+
+// Staleness is the time since the last successful sync.
+func (m *Mirror) Staleness() time.Duration {
+	return m.client.clock.Now().Sub(m.LastSync())
+}
+
+// Healthy returns nil when the mirror can answer IsRevoked: its initial sync
+// has completed and, with MaxStaleness set, the last successful sync is
+// recent enough. Otherwise it returns ErrMirrorNotReady, or an error
+// wrapping ErrMirrorStale and the last sync error.
+func (m *Mirror) Healthy() error {
+	m.mu.RLock()
+	ready, lastSync, lastErr := m.ready, m.lastSync, m.lastErr
+	m.mu.RUnlock()
+	if !ready {
+		return ErrMirrorNotReady
+	}
+	age := m.client.clock.Now().Sub(lastSync)
+	if m.opts.MaxStaleness <= 0 || age <= m.opts.MaxStaleness {
+		return nil
+	}
+	err := fmt.Errorf("%w: last synced %s ago", ErrMirrorStale, age.Round(time.Second))
+	if lastErr != nil {
+		err = fmt.Errorf("%w: %w", err, lastErr)
+	}
+	return err
+}
+
+// LastError returns the error of the latest failed sync, or nil if the
+// latest sync succeeded.
+func (m *Mirror) LastError() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastErr
+}
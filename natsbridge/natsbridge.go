@@ -0,0 +1,54 @@
+// Package natsbridge publishes revocation events to NATS for use with
+// eventbridge.Run.
+package natsbridge
+
+import (
+	"context"
+	"encoding/json"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/jwtrevoke/go-sdk/eventbridge"
+	"github.com/nats-io/nats.go"
+)
+
+const defaultSubjectPrefix = "jwtrevoke.events"
+
+var _ eventbridge.Publisher = (*Publisher)(nil)
+
+// Publisher sends each event as JSON to the subject <prefix>.<event type>,
+// e.g. jwtrevoke.events.revoked, so consumers can subscribe to
+// jwtrevoke.events.> or to a single type.
+type Publisher struct {
+	nc     *nats.Conn
+	prefix string
+}
+
+type Option func(*Publisher)
+
+// WithSubjectPrefix defaults to "jwtrevoke.events".
+func WithSubjectPrefix(prefix string) Option {
+	return func(p *Publisher) {
+		p.prefix = prefix
+	}
+}
+
+func New(nc *nats.Conn, options ...Option) *Publisher {
+	p := &Publisher{nc: nc, prefix: defaultSubjectPrefix}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Publish flushes after each message, so an event counts as published only
+// once the server has it.
+func (p *Publisher) Publish(ctx context.Context, ev jwtrevokeapi.RevocationEvent) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := p.nc.Publish(p.prefix+"."+string(ev.Type), data); err != nil {
+		return err
+	}
+	return p.nc.FlushWithContext(ctx)
+}
@@ -0,0 +1,173 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NotificationsService manages the rules that alert people about unusual
+// account activity.
+type NotificationsService struct {
+	client *Client
+}
+
+type NotificationTrigger string
+
+const (
+	// TriggerRevocationSpike fires when more than Threshold tokens are
+	// revoked within WindowSeconds.
+	TriggerRevocationSpike NotificationTrigger = "revocation_spike"
+	// TriggerQuotaThreshold fires when usage passes Threshold percent of the
+	// plan's quota.
+	TriggerQuotaThreshold NotificationTrigger = "quota_threshold"
+	// TriggerWebhookFailures fires after Threshold consecutive failed
+	// webhook deliveries.
+	TriggerWebhookFailures NotificationTrigger = "webhook_failures"
+)
+
+type NotificationChannelType string
+
+const (
+	ChannelEmail NotificationChannelType = "email"
+	ChannelSlack NotificationChannelType = "slack"
+)
+
+// NotificationChannel is where a rule's alerts go. Target is an email
+// address, or a Slack incoming webhook URL.
+type NotificationChannel struct {
+	Type   NotificationChannelType `json:"type"`
+	Target string                  `json:"target"`
+}
+
+type NotificationRule struct {
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	Trigger       NotificationTrigger   `json:"trigger"`
+	Threshold     int                   `json:"threshold"`
+	WindowSeconds int                   `json:"window_seconds,omitempty"`
+	Channels      []NotificationChannel `json:"channels"`
+	Enabled       bool                  `json:"enabled"`
+	CreatedAt     Timestamp             `json:"created_at"`
+}
+
+type CreateNotificationRuleRequest struct {
+	Name      string              `json:"name"`
+	Trigger   NotificationTrigger `json:"trigger"`
+	Threshold int                 `json:"threshold"`
+	// WindowSeconds is the period TriggerRevocationSpike counts over.
+	WindowSeconds int                   `json:"windowSeconds,omitempty"`
+	Channels      []NotificationChannel `json:"channels"`
+}
+
+// Validate checks that the rule has a trigger, a positive threshold, and at
+// least one channel, each with a target.
+func (r CreateNotificationRuleRequest) Validate() error {
+	if r.Trigger == "" {
+		return &ValidationError{Field: "trigger", Message: "must not be empty"}
+	}
+	if r.Threshold <= 0 {
+		return &ValidationError{Field: "threshold", Message: "must be positive"}
+	}
+	if r.Trigger == TriggerRevocationSpike && r.WindowSeconds <= 0 {
+		return &ValidationError{Field: "windowSeconds", Message: "must be positive for revocation spikes"}
+	}
+	if len(r.Channels) == 0 {
+		return &ValidationError{Field: "channels", Message: "must not be empty"}
+	}
+	for i, ch := range r.Channels {
+		if ch.Target == "" {
+			return &ValidationError{Field: fmt.Sprintf("channels[%d].target", i), Message: "must not be empty"}
+		}
+	}
+	return nil
+}
+
+// UpdateNotificationRuleRequest changes only the fields that are set.
+type UpdateNotificationRuleRequest struct {
+	Name          *string               `json:"name,omitempty"`
+	Threshold     *int                  `json:"threshold,omitempty"`
+	WindowSeconds *int                  `json:"windowSeconds,omitempty"`
+	Channels      []NotificationChannel `json:"channels,omitempty"`
+	Enabled       *bool                 `json:"enabled,omitempty"`
+}
+
+func (s *NotificationsService) Create(ctx context.Context, create CreateNotificationRuleRequest, opts ...CallOption) (*NotificationRule, error) {
+	if err := create.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(create)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/notifications", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[NotificationRule](ctx, s.client, req, "rule", opts...)
+}
+
+func (s *NotificationsService) List(ctx context.Context, opts ...CallOption) ([]NotificationRule, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/notifications", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]NotificationRule](ctx, s.client, req, "data", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (s *NotificationsService) Get(ctx context.Context, ruleID string, opts ...CallOption) (*NotificationRule, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/notifications/%s", s.client.baseURL, url.PathEscape(ruleID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[NotificationRule](ctx, s.client, req, "rule", opts...)
+}
+
+func (s *NotificationsService) Update(ctx context.Context, ruleID string, update UpdateNotificationRuleRequest, opts ...CallOption) (*NotificationRule, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/api/notifications/%s", s.client.baseURL, url.PathEscape(ruleID)), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[NotificationRule](ctx, s.client, req, "rule", opts...)
+}
+
+func (s *NotificationsService) Delete(ctx context.Context, ruleID string, opts ...CallOption) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/notifications/%s", s.client.baseURL, url.PathEscape(ruleID)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package jwtrevokeapi
+
+import (
+	"net/http"
+
+	"golang.org/x/oauth2"
+)
+
+// WithTokenSource authenticates with OAuth2 bearer tokens from ts, e.g. a
+// clientcredentials.Config for enterprise accounts, instead of an API key.
+// Tokens are cached and refreshed shortly before they expire.
+func WithTokenSource(ts oauth2.TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSource = oauth2.ReuseTokenSource(nil, ts)
+	}
+}
+
+func (c *Client) setBearerToken(req *http.Request) error {
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return err
+	}
+	c.lastSecret.Store(&token.AccessToken)
+	token.SetAuthHeader(req)
+	return nil
+}
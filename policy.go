@@ -0,0 +1,124 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Decision is the outcome of a request's revocation check, handed to a
+// DecisionFunc.
+type Decision struct {
+	// JwtID and SessionID are empty when the request carried none.
+	JwtID     string
+	SessionID string
+	Revoked   bool
+	// Err is set when the revocation status could not be determined.
+	Err error
+}
+
+// DecisionFunc combines a revocation check with other rules and reports
+// whether the request may proceed. An error is answered by OnError.
+type DecisionFunc func(r *http.Request, d Decision) (allow bool, err error)
+
+// OPADecision asks an Open Policy Agent server for each decision. It posts
+// to the data API endpoint, e.g. http://localhost:8181/v1/data/authz/allow,
+// with this input:
+//
+//	{
+//	  "method": "GET",
+//	  "path": "/orders/42",
+//	  "claims": {"sub": "user_123", "scope": "orders:read", ...},
+//	  "jti": "token_123",
+//	  "sid": "",
+//	  "claims_verified": true,
+//	  "revocation_status": "active"
+//	}
+//
+// revocation_status is "active", "revoked", or "unknown" when it could not
+// be determined. The claims are the bearer token's; claims_verified is only
+// true when MiddlewareOptions.KeySet verified them, so a rule that trusts
+// them should require it. The rule must evaluate to a boolean, or to an object with a boolean allow
+// field. An undefined result denies the request.
+func OPADecision(endpoint string, hc *http.Client) DecisionFunc {
+	if hc == nil {
+		hc = &http.Client{Timeout: 2 * time.Second}
+	}
+	return func(r *http.Request, d Decision) (bool, error) {
+		return evaluateOPA(r.Context(), hc, endpoint, opaInput(r, d))
+	}
+}
+
+func opaInput(r *http.Request, d Decision) map[string]interface{} {
+	status := "active"
+	switch {
+	case d.Err != nil:
+		status = "unknown"
+	case d.Revoked:
+		status = "revoked"
+	}
+	claims := map[string]interface{}{}
+	_, verified := ClaimsFromContext(r.Context())
+	if c, ok := UnverifiedClaimsFromContext(r.Context()); ok && c.payload != nil {
+		json.Unmarshal(c.payload, &claims)
+	} else if token, ok := bearerToken(r); ok {
+		if payload, err := tokenPayload(token); err == nil {
+			json.Unmarshal(payload, &claims)
+		}
+	}
+	return map[string]interface{}{
+		"method":            r.Method,
+		"path":              r.URL.Path,
+		"claims":            claims,
+		"claims_verified":   verified,
+		"jti":               d.JwtID,
+		"sid":               d.SessionID,
+		"revocation_status": status,
+	}
+}
+
+func evaluateOPA(ctx context.Context, hc *http.Client, endpoint string, input map[string]interface{}) (bool, error) {
+	body, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("jwt-revoke: policy query: unexpected status code: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("jwt-revoke: decoding policy result: %w", err)
+	}
+	if len(result.Result) == 0 {
+		return false, nil
+	}
+	var allow bool
+	if json.Unmarshal(result.Result, &allow) == nil {
+		return allow, nil
+	}
+	var object struct {
+		Allow bool `json:"allow"`
+	}
+	if err := json.Unmarshal(result.Result, &object); err != nil {
+		return false, fmt.Errorf("jwt-revoke: policy result is neither a boolean nor an object with allow")
+	}
+	return object.Allow, nil
+}
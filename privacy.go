@@ -0,0 +1,132 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// PrivacyService handles data-subject requests: exporting and erasing the
+// records the account holds about one user.
+type PrivacyService struct {
+	client *Client
+}
+
+// SubjectData is everything the account holds about a subject.
+type SubjectData struct {
+	Subject     string         `json:"subject"`
+	Revocations []RevokedToken `json:"revocations"`
+	// AuditLogs are the entries that mention the subject.
+	AuditLogs  []AuditLogEntry `json:"audit_logs"`
+	ExportedAt Timestamp       `json:"exported_at"`
+}
+
+type SubjectErasure struct {
+	Subject string `json:"subject"`
+	// Erased counts the records deleted outright, such as revocations that
+	// have expired.
+	Erased int `json:"erased"`
+	// Anonymized counts revocations still in effect, which are kept with
+	// the subject removed so the user's tokens stay revoked.
+	Anonymized int       `json:"anonymized"`
+	ErasedAt   Timestamp `json:"erased_at"`
+}
+
+// ExportSubject returns the records tied to the sub claim, e.g. to answer a
+// data subject access request. The data is returned as stored, regardless
+// of WithPIIRedaction.
+func (s *PrivacyService) ExportSubject(ctx context.Context, sub string, opts ...CallOption) (*SubjectData, error) {
+	if err := validateClaim("sub", sub); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/privacy/subjects/%s", s.client.baseURL, url.PathEscape(sub)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[SubjectData](ctx, s.client, req, "data", opts...)
+}
+
+// EraseSubject purges the records tied to the sub claim. It cannot be
+// undone; WithDryRun reports the counts without erasing anything.
+func (s *PrivacyService) EraseSubject(ctx context.Context, sub string, opts ...CallOption) (*SubjectErasure, error) {
+	if err := validateClaim("sub", sub); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/privacy/subjects/%s", s.client.baseURL, url.PathEscape(sub)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[SubjectErasure](ctx, s.client, req, "erasure", opts...)
+}
+
+type PIIMode int
+
+const (
+	// PIIKeep leaves personal data as the API returns it. This is the
+	// default.
+	PIIKeep PIIMode = iota
+	// PIIHash replaces email addresses with a keyed hash, so revocations by
+	// the same operator can still be correlated.
+	PIIHash
+	// PIIOmit removes email addresses.
+	PIIOmit
+)
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// WithPIIRedaction minimizes personal data, revoked_by_email and any other
+// email address, wherever the SDK writes or keeps it: exports, logs and
+// debug output, webhook delivery payloads returned by GetDelivery, and
+// revocation lists a Mirror or Cache persists to a Store. Responses returned
+// by other calls are left as they are. PIIHash uses HMAC-SHA256 with key;
+// keep it secret and stable, since without one a guessed address can be
+// checked against its hash.
+func WithPIIRedaction(mode PIIMode, key []byte) ClientOption {
+	return func(c *Client) {
+		c.piiMode = mode
+		c.piiKey = key
+	}
+}
+
+// redactEmail hashes or omits a single email address.
+func (c *Client) redactEmail(email string) string {
+	switch {
+	case email == "" || c.piiMode == PIIKeep:
+		return email
+	case c.piiMode == PIIOmit:
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.piiKey)
+	io.WriteString(mac, strings.ToLower(email))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// redactEmails hashes or masks the email addresses in free text, such as a
+// log line or a payload.
+func (c *Client) redactEmails(s string) string {
+	if c.piiMode == PIIKeep {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		if c.piiMode == PIIOmit {
+			return redacted
+		}
+		return c.redactEmail(email)
+	})
+}
+
+func (c *Client) minimizeToken(t RevokedToken) RevokedToken {
+	t.RevokedByEmail = c.redactEmail(t.RevokedByEmail)
+	return t
+}
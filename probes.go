@@ -0,0 +1,129 @@
+package jwtrevokeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthOptions lists the parts of the revocation subsystem a probe handler
+// checks. Nil parts are left out.
+type HealthOptions struct {
+	Cache        *Cache
+	Mirror       *Mirror
+	Subscription *Subscription
+}
+
+// HealthReport is the JSON body served by the probe handlers.
+type HealthReport struct {
+	// Status is "ok", or "unavailable" when the probe fails.
+	Status       string              `json:"status"`
+	Mirror       *MirrorHealth       `json:"mirror,omitempty"`
+	Cache        *CacheHealth        `json:"cache,omitempty"`
+	Subscription *SubscriptionHealth `json:"subscription,omitempty"`
+}
+
+type MirrorHealth struct {
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+	// Syncing is set while the background sync started by Start runs.
+	Syncing            bool       `json:"syncing"`
+	Entries            int        `json:"entries"`
+	LastSync           *Timestamp `json:"last_sync,omitempty"`
+	SnapshotAgeSeconds float64    `json:"snapshot_age_seconds,omitempty"`
+	LastSyncError      string     `json:"last_sync_error,omitempty"`
+}
+
+type CacheHealth struct {
+	Ready     bool   `json:"ready"`
+	Error     string `json:"error,omitempty"`
+	Entries   int    `json:"entries"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+type SubscriptionHealth struct {
+	Active bool   `json:"active"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewReadinessHandler serves a HealthReport with status 200 while every
+// part in opts can answer revocation checks, and 503 otherwise: before a
+// Mirror's initial sync or once it is stale, while a Cache fails closed,
+// or after a Subscription has ended. Point a Kubernetes readiness probe at
+// it so traffic is only routed to instances whose revocation checks work.
+func NewReadinessHandler(opts HealthOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := opts.report()
+		ok := (report.Mirror == nil || report.Mirror.Ready) &&
+			(report.Cache == nil || report.Cache.Ready) &&
+			(report.Subscription == nil || report.Subscription.Active)
+		report.write(w, ok)
+	})
+}
+
+// NewLivenessHandler serves the same report as NewReadinessHandler, but
+// only fails with 503 when background work has ended and will not resume:
+// a started Mirror's sync loop, or a Subscription. API outages do not fail
+// it, as restarting the process would not help.
+func NewLivenessHandler(opts HealthOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := opts.report()
+		ok := (opts.Mirror == nil || opts.Mirror.loop.Load() != mirrorEnded) &&
+			(report.Subscription == nil || report.Subscription.Active)
+		report.write(w, ok)
+	})
+}
+
+func (opts HealthOptions) report() HealthReport {
+	var report HealthReport
+	if m := opts.Mirror; m != nil {
+		h := &MirrorHealth{Syncing: m.loop.Load() == mirrorSyncing, Entries: m.Len()}
+		if err := m.Healthy(); err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Ready = true
+		}
+		if last := m.LastSync(); !last.IsZero() {
+			h.LastSync = &Timestamp{Time: last}
+			h.SnapshotAgeSeconds = time.Since(last).Seconds()
+		}
+		if err := m.LastError(); err != nil {
+			h.LastSyncError = err.Error()
+		}
+		report.Mirror = h
+	}
+	if c := opts.Cache; c != nil {
+		h := &CacheHealth{Entries: c.Stats().Entries}
+		if err := c.Ready(); err != nil {
+			h.Error = err.Error()
+		} else {
+			h.Ready = true
+		}
+		if err := c.LastError(); err != nil {
+			h.LastError = err.Error()
+		}
+		report.Cache = h
+	}
+	if sub := opts.Subscription; sub != nil {
+		h := &SubscriptionHealth{}
+		var err error
+		if h.Active, err = sub.Active(); err != nil {
+			h.Error = err.Error()
+		}
+		report.Subscription = h
+	}
+	return report
+}
+
+func (report HealthReport) write(w http.ResponseWriter, ok bool) {
+	status := http.StatusOK
+	report.Status = "ok"
+	if !ok {
+		status = http.StatusServiceUnavailable
+		report.Status = "unavailable"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
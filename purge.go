@@ -0,0 +1,47 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type PurgeResult struct {
+	PurgedCount int `json:"purged_count"`
+}
+
+type purgeRequest struct {
+	Before time.Time `json:"before"`
+}
+
+// Purge asks the API to delete revocations whose expiry date is before
+// before. Their tokens have expired, so the entries no longer block
+// anything. before may not be in the future, since that would lift
+// revocations of tokens that are still valid. Purged entries show up as
+// deleted events in Changes.
+func (s *RevocationsService) Purge(ctx context.Context, before time.Time, opts ...CallOption) (*PurgeResult, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	switch {
+	case before.IsZero():
+		return nil, &ValidationError{Field: "before", Message: "must not be zero"}
+	case before.After(s.client.clock.Now()):
+		return nil, &ValidationError{Field: "before", Message: "must not be in the future"}
+	}
+
+	body, err := json.Marshal(purgeRequest{Before: before.UTC()})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/revocations/purge", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[PurgeResult](ctx, s.client, req, "", opts...)
+}
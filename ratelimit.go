@@ -0,0 +1,156 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// adaptivePacingThreshold is the share of the quota left at which
+	// adaptive pacing starts spacing requests out.
+	adaptivePacingThreshold = 0.2
+	// maxRetryAfter caps the Retry-After wait of a 429, so a bogus header
+	// cannot park a call for hours.
+	maxRetryAfter = time.Minute
+	// The X-RateLimit headers in canonical form, so reading them does not
+	// allocate.
+	rateLimitLimitHeader     = "X-Ratelimit-Limit"
+	rateLimitRemainingHeader = "X-Ratelimit-Remaining"
+	rateLimitResetHeader     = "X-Ratelimit-Reset"
+)
+
+// RateLimitInfo is the API's rate-limit state as reported on a response.
+// Limit and Remaining are -1 when the response did not say.
+type RateLimitInfo struct {
+	// Limited is set for 429 responses.
+	Limited   bool
+	Limit     int
+	Remaining int
+	// Reset is when the quota is replenished; zero if unknown.
+	Reset time.Time
+	// RetryAfter is the wait a 429 asked for; zero if none.
+	RetryAfter time.Duration
+}
+
+// WithOnRateLimited calls fn for every 429 response, and for every response
+// reporting fewer than threshold requests left in the quota, e.g. to alert
+// before calls start failing. A threshold of zero only reports 429s. fn
+// runs on the request's goroutine and should return quickly.
+func WithOnRateLimited(threshold int, fn func(ctx context.Context, info RateLimitInfo)) ClientOption {
+	return func(c *Client) {
+		c.rateLimitThreshold = threshold
+		c.onRateLimited = fn
+	}
+}
+
+// WithAdaptivePacing slows requests down as the quota nears exhaustion:
+// once less than a fifth of it is left, requests are spaced out evenly over
+// the time until it resets, and with none left they wait for the reset.
+// Waits end early when the request's context is done.
+func WithAdaptivePacing() ClientOption {
+	return func(c *Client) {
+		c.adaptivePacing = true
+	}
+}
+
+// rateLimitState is the last quota the API reported, shared by a client and
+// the clients derived from it.
+type rateLimitState struct {
+	mu       sync.Mutex
+	last     RateLimitInfo
+	nextSend time.Time
+}
+
+func newRateLimitState() *rateLimitState {
+	return &rateLimitState{last: RateLimitInfo{Limit: -1, Remaining: -1}}
+}
+
+// observeRateLimit records the rate-limit headers of resp and calls the
+// OnRateLimited hook.
+func (c *Client) observeRateLimit(ctx context.Context, resp *http.Response) {
+	info := parseRateLimit(resp)
+	if info.Remaining >= 0 {
+		c.rateLimits.mu.Lock()
+		c.rateLimits.last = info
+		c.rateLimits.mu.Unlock()
+	}
+	if c.onRateLimited != nil && (info.Limited || (info.Remaining >= 0 && info.Remaining < c.rateLimitThreshold)) {
+		c.onRateLimited(ctx, info)
+	}
+}
+
+// pace waits for the request's slot when adaptive pacing is on.
+func (c *Client) pace(ctx context.Context) error {
+	if !c.adaptivePacing {
+		return nil
+	}
+	s := c.rateLimits
+	s.mu.Lock()
+	now := c.clock.Now()
+	info := s.last
+	var wait time.Duration
+	switch {
+	case info.Remaining < 0 || info.Reset.IsZero() || !info.Reset.After(now):
+	case info.Remaining == 0:
+		wait = info.Reset.Sub(now)
+	case info.Limit > 0 && float64(info.Remaining) < adaptivePacingThreshold*float64(info.Limit):
+		// Slots are handed out in turn so concurrent requests are spread
+		// out too.
+		interval := info.Reset.Sub(now) / time.Duration(info.Remaining)
+		slot := now
+		if s.nextSend.After(now) {
+			slot = s.nextSend
+		}
+		s.nextSend = slot.Add(interval)
+		wait = slot.Sub(now)
+	}
+	s.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, c.clock, wait)
+}
+
+// rateLimitWait is how long to wait before retrying the 429 resp: its
+// Retry-After, capped at maxRetryAfter, or else WithRateLimitDelay.
+func (c *Client) rateLimitWait(resp *http.Response) time.Duration {
+	if wait := parseRateLimit(resp).RetryAfter; wait > 0 {
+		return min(wait, maxRetryAfter)
+	}
+	return c.rateLimitDelay
+}
+
+func parseRateLimit(resp *http.Response) RateLimitInfo {
+	info := RateLimitInfo{
+		Limited:   resp.StatusCode == http.StatusTooManyRequests,
+		Limit:     headerInt(resp.Header, rateLimitLimitHeader),
+		Remaining: headerInt(resp.Header, rateLimitRemainingHeader),
+	}
+	if reset := headerInt(resp.Header, rateLimitResetHeader); reset > 0 {
+		info.Reset = time.Unix(int64(reset), 0)
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			info.RetryAfter = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(v); err == nil {
+			info.RetryAfter = time.Until(at)
+		}
+	}
+	return info
+}
+
+// headerInt returns the non-negative integer value of a header, or -1.
+func headerInt(h http.Header, name string) int {
+	v := h.Get(name)
+	if v == "" {
+		return -1
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package jwtrevokeapi
+
+// ReasonCode is the machine-readable reason for a revocation. The constants
+// below are the codes the API recognizes; other values are accepted and
+// kept as is, which is how revocations made before reason codes existed
+// come back.
+type ReasonCode string
+
+const (
+	ReasonCompromised      ReasonCode = "compromised"
+	ReasonLogout           ReasonCode = "logout"
+	ReasonPasswordChange   ReasonCode = "password_change"
+	ReasonAdminAction      ReasonCode = "admin_action"
+	ReasonPermissionChange ReasonCode = "permission_change"
+	ReasonAccountDeleted   ReasonCode = "account_deleted"
+	ReasonSuspicious       ReasonCode = "suspicious_activity"
+	ReasonOther            ReasonCode = "other"
+)
+
+var knownReasons = map[ReasonCode]bool{
+	ReasonCompromised:      true,
+	ReasonLogout:           true,
+	ReasonPasswordChange:   true,
+	ReasonAdminAction:      true,
+	ReasonPermissionChange: true,
+	ReasonAccountDeleted:   true,
+	ReasonSuspicious:       true,
+	ReasonOther:            true,
+}
+
+// Known reports whether r is one of the predefined reason codes.
+func (r ReasonCode) Known() bool {
+	return knownReasons[r]
+}
@@ -0,0 +1,137 @@
+// Package redisstore provides a Redis-backed jwtrevokeapi.Store so a fleet of
+// services can share one mirrored revocation list and one sync loop.
+package redisstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultPrefix = "jwtrevoke:"
+
+var (
+	_ jwtrevokeapi.Store  = (*Store)(nil)
+	_ jwtrevokeapi.Locker = (*Store)(nil)
+)
+
+// extendLock refreshes a lock only while it is still held by this store.
+var extendLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+type Store struct {
+	rdb    redis.UniversalClient
+	prefix string
+	owner  string
+}
+
+type Option func(*Store)
+
+// WithPrefix namespaces all keys, e.g. per environment. Defaults to "jwtrevoke:".
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+func New(rdb redis.UniversalClient, options ...Option) *Store {
+	s := &Store{rdb: rdb, prefix: defaultPrefix, owner: newOwnerID()}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.rdb.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.rdb.Set(ctx, s.prefix+key, value, ttl).Err()
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.rdb.Del(ctx, s.prefix+key).Err()
+}
+
+// Scan visits the keys of every master when rdb is a cluster client, since
+// SCAN only covers the node it is sent to. fn is never called concurrently.
+func (s *Store) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	cluster, ok := s.rdb.(*redis.ClusterClient)
+	if !ok {
+		return s.scanNode(ctx, s.rdb, prefix, fn)
+	}
+	var mu sync.Mutex
+	var stopped error
+	return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		return s.scanNode(ctx, node, prefix, func(key string, value []byte) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if stopped == nil {
+				stopped = fn(key, value)
+			}
+			return stopped
+		})
+	})
+}
+
+func (s *Store) scanNode(ctx context.Context, node redis.Cmdable, prefix string, fn func(key string, value []byte) error) error {
+	iter := node.Scan(ctx, 0, s.prefix+prefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		fullKey := iter.Val()
+		value, err := s.rdb.Get(ctx, fullKey).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(fullKey[len(s.prefix):], value); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// TryLock acquires or extends the named lock for ttl. It reports false while
+// another Store instance holds it.
+func (s *Store) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := s.rdb.SetNX(ctx, s.prefix+key, s.owner, ttl).Result()
+	if err != nil || ok {
+		return ok, err
+	}
+	extended, err := extendLock.Run(ctx, s.rdb, []string{s.prefix + key}, s.owner, ttl.Milliseconds()).Int()
+	return extended == 1, err
+}
+
+func (s *Store) Unlock(ctx context.Context, key string) error {
+	return releaseLock.Run(ctx, s.rdb, []string{s.prefix + key}, s.owner).Err()
+}
+
+func newOwnerID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
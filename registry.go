@@ -0,0 +1,170 @@
+package jwtrevokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownEnvironment is passed to MiddlewareOptions.OnError for tokens
+// for which Registry.Middleware selects no environment and no default is
+// set.
+var ErrUnknownEnvironment = errors.New("jwt-revoke: no environment selected for token")
+
+// Registry holds named clients, one per environment or region such as
+// "prod", "staging", or "eu", each with its own key and base URL and its own
+// Cache. Its Middleware picks the environment per request.
+type Registry struct {
+	cacheOpts CacheOptions
+
+	mu         sync.RWMutex
+	envs       map[string]registryEnv
+	defaultEnv string
+}
+
+type registryEnv struct {
+	client *Client
+	cache  *Cache
+}
+
+// NewRegistry returns an empty registry. Every registered client gets a
+// Cache built with cacheOpts.
+func NewRegistry(cacheOpts CacheOptions) *Registry {
+	return &Registry{cacheOpts: cacheOpts, envs: make(map[string]registryEnv)}
+}
+
+// Add creates a client for the environment name, as NewClient does, and
+// registers it.
+func (r *Registry) Add(name, apiKey string, options ...ClientOption) *Client {
+	client := NewClient(apiKey, options...)
+	r.Register(name, client)
+	return client
+}
+
+// Register adds client under name, replacing any client registered before
+// and closing its cache.
+func (r *Registry) Register(name string, client *Client) {
+	env := registryEnv{client: client, cache: NewCache(client.Revocations, r.cacheOpts)}
+	r.mu.Lock()
+	replaced, ok := r.envs[name]
+	r.envs[name] = env
+	r.mu.Unlock()
+	if ok {
+		replaced.cache.Close()
+	}
+}
+
+func (r *Registry) Client(name string) (*Client, bool) {
+	r.mu.RLock()
+	env, ok := r.envs[name]
+	r.mu.RUnlock()
+	return env.client, ok
+}
+
+// Cache returns the cache Middleware checks requests for name against.
+func (r *Registry) Cache(name string) (*Cache, bool) {
+	r.mu.RLock()
+	env, ok := r.envs[name]
+	r.mu.RUnlock()
+	return env.cache, ok
+}
+
+// SetDefault makes Middleware check tokens for which it selects no
+// environment, such as ones from an unknown issuer, against the environment
+// name. Without a default they are answered by OnError.
+func (r *Registry) SetDefault(name string) {
+	r.mu.Lock()
+	r.defaultEnv = name
+	r.mu.Unlock()
+}
+
+// Names returns the registered environments in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.envs))
+	for name := range r.envs {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// Middleware is like the package-level Middleware, checking each request
+// against the cache of the environment env selects for it. Requests without
+// a token are passed through, as Middleware does. Tokens for which env
+// selects no environment are checked against the SetDefault environment,
+// or answered by opts.OnError with ErrUnknownEnvironment, so a token can
+// never skip the check by naming no known environment. Tokens naming an
+// environment that is not registered are answered by opts.OnError too.
+func (r *Registry) Middleware(env func(*http.Request) (string, bool), opts MiddlewareOptions) func(http.Handler) http.Handler {
+	opts.setDefaults()
+
+	return func(next http.Handler) http.Handler {
+		var mu sync.Mutex
+		// handlers holds one handler per environment, rebuilt when the
+		// environment is registered again.
+		handlers := make(map[string]registryHandler)
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if opts.skip(req) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			name, ok := env(req)
+			if !ok {
+				if _, hasToken := requestToken(req, opts.TokenSources); !hasToken {
+					next.ServeHTTP(w, req)
+					return
+				}
+				r.mu.RLock()
+				name = r.defaultEnv
+				r.mu.RUnlock()
+				if name == "" {
+					opts.OnError(w, req, ErrUnknownEnvironment)
+					return
+				}
+			}
+			cache, ok := r.Cache(name)
+			if !ok {
+				opts.OnError(w, req, fmt.Errorf("jwt-revoke: no client registered for environment %q", name))
+				return
+			}
+			mu.Lock()
+			h, ok := handlers[name]
+			if !ok || h.cache != cache {
+				h = registryHandler{cache: cache, handler: Middleware(cache, opts)(next)}
+				handlers[name] = h
+			}
+			mu.Unlock()
+			h.handler.ServeHTTP(w, req)
+		})
+	}
+}
+
+type registryHandler struct {
+	cache   *Cache
+	handler http.Handler
+}
+
+// IssuerEnvironment selects the environment from the iss claim of the
+// bearer token, using issuers to map issuer URLs to environment names. The
+// claim is read without verifying the token, so only rely on it when the
+// application verifies each token with the keys of the issuer it names;
+// tokens from unmapped issuers go to the Registry's default environment or
+// are rejected.
+func IssuerEnvironment(issuers map[string]string) func(*http.Request) (string, bool) {
+	return func(r *http.Request) (string, bool) {
+		token, ok := bearerToken(r)
+		if !ok {
+			return "", false
+		}
+		claims, err := parseClaims(token)
+		if err != nil {
+			return "", false
+		}
+		name, ok := issuers[claims.Issuer]
+		return name, ok
+	}
+}
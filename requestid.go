@@ -0,0 +1,49 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestIDHeader is X-Request-ID in canonical form, so setting and reading
+// it does not allocate.
+const requestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID makes calls made with ctx send id as their
+// X-Request-ID, e.g. to propagate the ID of the inbound request being
+// handled. Without it every attempt gets a freshly generated ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// CaptureRequestID stores the request ID of a call's final attempt in dst,
+// as reported by the server or, if it sends none, the ID the SDK sent. Quote
+// it in support tickets.
+func CaptureRequestID(dst *string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.requestID = dst
+	}
+}
+
+func setRequestID(ctx context.Context, req *http.Request) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	if id == "" {
+		id = newIdempotencyKey()
+	}
+	req.Header.Set(requestIDHeader, id)
+	return id
+}
+
+// responseRequestID prefers the server's ID for the request over the one
+// that was sent.
+func responseRequestID(resp *http.Response) string {
+	if id := resp.Header.Get(requestIDHeader); id != "" {
+		return id
+	}
+	if resp.Request != nil {
+		return resp.Request.Header.Get(requestIDHeader)
+	}
+	return ""
+}
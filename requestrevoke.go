@@ -0,0 +1,77 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrNoToken is returned by RevokeFromRequest when the request carries no
+// token in the configured sources.
+var ErrNoToken = errors.New("jwt-revoke: request carries no token")
+
+// WithTokenCookie makes RevokeFromRequest read the token only from the named
+// cookie, for browser sessions that keep the JWT in a cookie. It is
+// WithRequestTokenSources(TokenFromCookie(name)).
+func WithTokenCookie(name string) ClientOption {
+	return WithRequestTokenSources(TokenFromCookie(name))
+}
+
+// WithRequestTokenSources sets where RevokeFromRequest looks for the token,
+// in order. They should match the sources the request was authenticated
+// with, so a logout cannot be pointed at a different token. Defaults to the
+// Authorization header.
+func WithRequestTokenSources(sources ...TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokenSources = sources
+	}
+}
+
+// WithRequestKeySet makes RevokeFromRequest verify the token's signature
+// with keys before revoking it. A token that fails is not revoked and the
+// error wraps ErrInvalidToken.
+func WithRequestKeySet(keys KeySet) ClientOption {
+	return func(c *Client) {
+		c.tokenKeySet = keys
+	}
+}
+
+// RevokeFromRequest revokes the token r was authenticated with, as a logout
+// handler does. When Middleware verified the token with a KeySet, the
+// verified claims in r's context are revoked. Otherwise the token is read
+// from the WithRequestTokenSources sources, by default the Authorization
+// header, and verified when WithRequestKeySet is set. Without either, the
+// signature is not verified, so call it only after the request has been
+// authenticated. The expiry is derived from the token's exp claim, and a
+// token that has already expired is not an error.
+func (s *RevocationsService) RevokeFromRequest(ctx context.Context, r *http.Request, reason ReasonCode, opts ...CallOption) error {
+	var req RevokeRequest
+	if claims, ok := ClaimsFromContext(r.Context()); ok && claims.JwtID != "" {
+		req = RevokeRequest{JwtID: claims.JwtID, Reason: reason}
+		if !claims.ExpiresAt.IsZero() {
+			expiry := claims.ExpiresAt.Add(s.client.expirySkew)
+			req.ExpiryDate = &expiry
+		}
+	} else {
+		token, ok := requestToken(r, s.client.tokenSources)
+		if !ok {
+			return ErrNoToken
+		}
+		if s.client.tokenKeySet != nil {
+			if _, err := s.client.tokenKeySet.VerifySignature(ctx, token); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidToken, err)
+			}
+		}
+		var verr *ValidationError
+		req, verr = RevokeRequest{Reason: reason, Token: token}.withTokenClaims(s.client.expirySkew)
+		if verr != nil {
+			return verr
+		}
+	}
+	if req.ExpiryDate != nil && !req.ExpiryDate.After(s.client.clock.Now()) {
+		return nil
+	}
+	_, err := s.Revoke(ctx, req, opts...)
+	return err
+}
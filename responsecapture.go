@@ -0,0 +1,24 @@
+package jwtrevokeapi
+
+import "net/http"
+
+// ResponseInfo describes the HTTP response to a call's final attempt.
+type ResponseInfo struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the server's request ID, or the one the SDK sent.
+	RequestID string
+	// Attempts is the number of requests sent, counting retries; zero when
+	// WithHTTPCache answered the call.
+	Attempts int
+}
+
+// WithResponseCapture stores details of the response to a call's final
+// attempt in dst, including for calls that fail with a *ClientError, e.g.
+// to log rate-limit headers or assert on them in tests. dst is left
+// unchanged when no response was received.
+func WithResponseCapture(dst *ResponseInfo) CallOption {
+	return func(cfg *callConfig) {
+		cfg.response = dst
+	}
+}
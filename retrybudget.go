@@ -0,0 +1,89 @@
+package jwtrevokeapi
+
+import (
+	"sync"
+	"time"
+)
+
+const retryBudgetBuckets = 10
+
+// RetryBudget caps retries as a share of recent requests, so that during an
+// API brownout the client sheds retries instead of multiplying the load.
+type RetryBudget struct {
+	// Ratio is the share of requests that may be retries, e.g. 0.2.
+	Ratio float64
+	// Window is the sliding window requests and retries are counted over.
+	// Defaults to 10s.
+	Window time.Duration
+	// MinRetries are always allowed per window, so a quiet client can still
+	// retry the odd failure. Defaults to 10.
+	MinRetries int
+}
+
+// WithRetryBudget limits retries across every request the client, and copies
+// made with With, send. Once the budget is spent, a failing attempt is
+// returned instead of retried.
+func WithRetryBudget(budget RetryBudget) ClientOption {
+	return func(c *Client) {
+		if budget.Window <= 0 {
+			budget.Window = 10 * time.Second
+		}
+		if budget.MinRetries == 0 {
+			budget.MinRetries = 10
+		}
+		c.retryBudget = &retryBudget{RetryBudget: budget}
+	}
+}
+
+// retryBudget counts requests and retries in buckets covering the window.
+type retryBudget struct {
+	RetryBudget
+
+	mu      sync.Mutex
+	buckets [retryBudgetBuckets]budgetBucket
+}
+
+type budgetBucket struct {
+	start             int64
+	requests, retries int
+}
+
+// bucket returns the current bucket, clearing it if it belongs to an earlier
+// rotation of the ring.
+func (b *retryBudget) bucket(now time.Time) *budgetBucket {
+	width := int64(b.Window) / retryBudgetBuckets
+	start := now.UnixNano() / width * width
+	bucket := &b.buckets[start/width%retryBudgetBuckets]
+	if bucket.start != start {
+		*bucket = budgetBucket{start: start}
+	}
+	return bucket
+}
+
+func (b *retryBudget) recordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bucket(time.Now()).requests++
+}
+
+// withdraw reports whether a retry fits in the budget and counts it if so.
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	current := b.bucket(now)
+
+	oldest := now.Add(-b.Window).UnixNano()
+	var requests, retries int
+	for _, bucket := range b.buckets {
+		if bucket.start > oldest {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	if retries >= b.MinRetries && float64(retries+1) > b.Ratio*float64(requests) {
+		return false
+	}
+	current.retries++
+	return true
+}
@@ -0,0 +1,61 @@
+package jwtrevokeapi
+
+import "net/http"
+
+// POSTRetryPolicy decides whether POSTs, such as revocations, are resent
+// after a connection error or a retryable status, when the API may already
+// have acted on them.
+type POSTRetryPolicy int
+
+const (
+	// RetryPOSTWithIdempotencyKey resends POSTs only when they carry an
+	// Idempotency-Key, which the SDK adds to every POST unless key
+	// generation fails. It is the default.
+	RetryPOSTWithIdempotencyKey POSTRetryPolicy = iota
+	// RetryPOSTAlways resends POSTs as other requests are.
+	RetryPOSTAlways
+	// RetryPOSTNever sends POSTs once. Rate-limited POSTs, which the API
+	// has not processed, are still retried.
+	RetryPOSTNever
+)
+
+// WithPOSTRetries sets the POSTRetryPolicy.
+func WithPOSTRetries(policy POSTRetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.postRetries = policy
+	}
+}
+
+// WithRetryableStatusCodes replaces the statuses retried with backoff,
+// which default to every 5xx. Other statuses fail with a *ClientError right
+// away. 429 responses are always retried after the rate-limit delay.
+func WithRetryableStatusCodes(codes ...int) ClientOption {
+	return func(c *Client) {
+		c.retryableStatus = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			c.retryableStatus[code] = true
+		}
+	}
+}
+
+func (c *Client) retryableStatusCode(code int) bool {
+	if c.retryableStatus == nil {
+		return code >= 500
+	}
+	return c.retryableStatus[code]
+}
+
+// resendAllowed applies the POSTRetryPolicy to req.
+func (c *Client) resendAllowed(req *http.Request) bool {
+	if req.Method != http.MethodPost {
+		return true
+	}
+	switch c.postRetries {
+	case RetryPOSTAlways:
+		return true
+	case RetryPOSTNever:
+		return false
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
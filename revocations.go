@@ -0,0 +1,8 @@
+package jwtrevokeapi
+
+// RevocationsService groups the operations on revoked tokens: revoking,
+// reading, updating and deleting entries, listing and streaming them, and
+// the revocation statistics.
+type RevocationsService struct {
+	client *Client
+}
@@ -0,0 +1,87 @@
+package jwtrevokeapi
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats is a snapshot of a client's in-process counters, for alerting
+// without a metrics integration. Counters start at zero when the client is
+// created and are shared with copies made by With.
+type ClientStats struct {
+	// RequestsByStatus counts responses by HTTP status code, including
+	// those of attempts that were retried.
+	RequestsByStatus map[int]int64
+	// TransportErrors counts attempts that got no response at all.
+	TransportErrors int64
+	Retries         int64
+	// RetriesShed counts retries skipped because the retry budget was spent.
+	RetriesShed int64
+	// RateLimited counts 429 responses.
+	RateLimited int64
+	// CacheHits and CacheMisses cover every Cache built on the client.
+	CacheHits   int64
+	CacheMisses int64
+	// LastSnapshot is when a Mirror built on the client last synced, and
+	// SnapshotAge how long ago that was. Both are zero without a mirror.
+	LastSnapshot time.Time
+	SnapshotAge  time.Duration
+}
+
+type runtimeCounters struct {
+	mu       sync.Mutex
+	byStatus map[int]int64
+
+	transportErrors, retries, rateLimited atomic.Int64
+	retriesShed                           atomic.Int64
+	cacheHits, cacheMisses                atomic.Int64
+	lastSnapshot                          atomic.Int64
+}
+
+func newRuntimeCounters() *runtimeCounters {
+	return &runtimeCounters{byStatus: make(map[int]int64)}
+}
+
+func (r *runtimeCounters) recordStatus(code int) {
+	r.mu.Lock()
+	r.byStatus[code]++
+	r.mu.Unlock()
+}
+
+func (r *runtimeCounters) recordCache(hit bool) {
+	if hit {
+		r.cacheHits.Add(1)
+	} else {
+		r.cacheMisses.Add(1)
+	}
+}
+
+func (r *runtimeCounters) recordSnapshot(at time.Time) {
+	r.lastSnapshot.Store(at.UnixNano())
+}
+
+// RuntimeStats returns the client's counters. It is unrelated to Stats,
+// which reports account-wide figures from the API.
+func (c *Client) RuntimeStats() ClientStats {
+	r := c.stats
+	r.mu.Lock()
+	byStatus := maps.Clone(r.byStatus)
+	r.mu.Unlock()
+
+	stats := ClientStats{
+		RequestsByStatus: byStatus,
+		TransportErrors:  r.transportErrors.Load(),
+		Retries:          r.retries.Load(),
+		RetriesShed:      r.retriesShed.Load(),
+		RateLimited:      r.rateLimited.Load(),
+		CacheHits:        r.cacheHits.Load(),
+		CacheMisses:      r.cacheMisses.Load(),
+	}
+	if ns := r.lastSnapshot.Load(); ns != 0 {
+		stats.LastSnapshot = time.Unix(0, ns)
+		stats.SnapshotAge = time.Since(stats.LastSnapshot)
+	}
+	return stats
+}
@@ -0,0 +1,264 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// BulkTask is one unit of a scheduled job, usually a single API call.
+type BulkTask struct {
+	// Items is the number of items the task processes, e.g. revocations in
+	// a batch call, for progress reporting.
+	Items int
+	Run   func(ctx context.Context) error
+}
+
+// BulkProgress reports how far a scheduled job has got.
+type BulkProgress struct {
+	// Done counts the items finished, Failed the ones among them whose task
+	// returned an error.
+	Done   int
+	Failed int
+	// Total is the number of items expected, or zero when unknown.
+	Total   int
+	Elapsed time.Duration
+	// Rate is the items finished per second so far.
+	Rate float64
+	// ETA estimates the time left; zero when Total is unknown.
+	ETA time.Duration
+}
+
+type BulkSchedulerOptions struct {
+	// Concurrency limits the tasks Run keeps in flight. Defaults to the
+	// client's WithConcurrency setting.
+	Concurrency int
+	// Reserve is the share of the quota, between 0 and 1, left for the
+	// client's other traffic, e.g. 0.2 so a job never uses the last fifth.
+	Reserve float64
+	// Total is the number of items expected, for the ETA of jobs that do not
+	// know it upfront, such as Import. Run adds the items of its tasks.
+	Total int
+	// OnProgress is called after each task finishes. Calls do not overlap;
+	// fn should return quickly, as tasks wait for it.
+	OnProgress func(BulkProgress)
+}
+
+// BulkScheduler paces a bulk job by the quota the API reports in its
+// X-RateLimit headers, so the job finishes as fast as the quota allows
+// without running into a wall of 429s. While the work left fits in what
+// remains of the quota, tasks run at full concurrency; otherwise they are
+// spread evenly until the quota resets, and with none left they wait for
+// the reset. A task failing with a 429 pauses the whole job until the reset
+// and is then retried, up to the client's WithMaxRetries. Use one scheduler
+// per job, as progress covers everything it has run.
+type BulkScheduler struct {
+	client *Client
+	opts   BulkSchedulerOptions
+
+	mu          sync.Mutex
+	started     time.Time
+	total       int
+	done        int
+	failed      int
+	pending     int
+	nextSend    time.Time
+	pausedUntil time.Time
+}
+
+func NewBulkScheduler(client *Client, opts BulkSchedulerOptions) *BulkScheduler {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = client.concurrency
+	}
+	return &BulkScheduler{client: client, opts: opts, started: client.clock.Now(), total: opts.Total}
+}
+
+// Run runs tasks, bounded by Concurrency and paced by the quota. Unlike
+// RevokeBatch, a failed task does not stop the others; Run returns their
+// errors joined, in task order, or ctx's error for tasks it never started.
+func (s *BulkScheduler) Run(ctx context.Context, tasks []BulkTask) error {
+	s.mu.Lock()
+	for _, t := range tasks {
+		s.total += t.Items
+	}
+	s.pending += len(tasks)
+	s.mu.Unlock()
+
+	errs := make([]error, len(tasks))
+	sem := make(chan struct{}, s.opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, t := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = context.Cause(ctx)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = s.do(ctx, func(ctx context.Context) (int, error) {
+				return t.Items, t.Run(ctx)
+			})
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// RevokeBatch revokes revocations in chunks of the account's
+// Capabilities.MaxBatchSize, scheduled like Run. Every revocation is
+// validated before anything is sent. It returns the tokens of the chunks
+// that succeeded, in request order, along with the errors of those that did
+// not.
+func (s *BulkScheduler) RevokeBatch(ctx context.Context, revocations []RevokeRequest, opts ...CallOption) ([]RevokedToken, error) {
+	revocations, err := s.client.resolveBatch(revocations)
+	if err != nil {
+		return nil, err
+	}
+
+	size := s.client.batchSize(ctx, opts...)
+	chunks := make([][]RevokedToken, (len(revocations)+size-1)/size)
+	tasks := make([]BulkTask, len(chunks))
+	for i := range chunks {
+		chunk := revocations[i*size : min((i+1)*size, len(revocations))]
+		tasks[i] = BulkTask{Items: len(chunk), Run: func(ctx context.Context) error {
+			tokens, err := s.client.revokeBatch(ctx, chunk, opts...)
+			chunks[i] = tokens
+			return err
+		}}
+	}
+	err = s.Run(ctx, tasks)
+
+	tokens := make([]RevokedToken, 0, len(revocations))
+	for _, chunk := range chunks {
+		tokens = append(tokens, chunk...)
+	}
+	return tokens, err
+}
+
+// scheduledKey marks the context of a BulkScheduler task, whose 429s the
+// scheduler retries instead of the client.
+type scheduledKey struct{}
+
+func isScheduled(ctx context.Context) bool {
+	scheduled, _ := ctx.Value(scheduledKey{}).(bool)
+	return scheduled
+}
+
+// do runs one task once its slot comes, retrying it after 429s. The client
+// returns 429s within the task at once, so a request is not retried by both.
+// fn returns the number of items it processed.
+func (s *BulkScheduler) do(ctx context.Context, fn func(context.Context) (int, error)) error {
+	ctx = context.WithValue(ContextWithBulkPriority(ctx), scheduledKey{}, true)
+	for attempt := 0; ; attempt++ {
+		if err := s.acquire(ctx, attempt == 0); err != nil {
+			return err
+		}
+		items, err := fn(ctx)
+		if err != nil && IsRateLimited(err) && attempt < s.client.maxRetries && ctx.Err() == nil {
+			until := s.pause()
+			s.client.log(ctx, slog.LevelWarn, "jwtrevoke: bulk job rate limited, pausing",
+				"attempt", attempt+1, "until", until)
+			continue
+		}
+		s.finish(items, err)
+		return err
+	}
+}
+
+// acquire waits for the next task's slot.
+func (s *BulkScheduler) acquire(ctx context.Context, first bool) error {
+	info, reset := s.quota()
+
+	s.mu.Lock()
+	now := s.client.clock.Now()
+	start := now
+	if s.pausedUntil.After(start) {
+		start = s.pausedUntil
+	}
+	if info.Remaining >= 0 && reset.After(now) {
+		usable := info.Remaining - int(s.opts.Reserve*float64(max(info.Limit, 0)))
+		switch {
+		case usable <= 0:
+			if reset.After(start) {
+				start = reset
+			}
+		case s.pending > 0 && s.pending <= usable:
+			// The rest of the job fits in the quota.
+		default:
+			interval := reset.Sub(now) / time.Duration(usable)
+			if s.nextSend.After(start) {
+				start = s.nextSend
+			}
+			s.nextSend = start.Add(interval)
+		}
+	}
+	if first && s.pending > 0 {
+		s.pending--
+	}
+	s.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		return sleepContext(ctx, s.client.clock, wait)
+	}
+	return ctx.Err()
+}
+
+// pause holds back every task until the quota resets, or for the client's
+// rate limit delay when the reset is unknown, and returns when it resumes.
+func (s *BulkScheduler) pause() time.Time {
+	_, reset := s.quota()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.client.clock.Now()
+	until := now.Add(max(s.client.rateLimitDelay, time.Second))
+	if reset.After(now) {
+		until = reset
+	}
+	if until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+	return s.pausedUntil
+}
+
+// quota returns the last quota the API reported and when it resets. The
+// reset is sent in whole seconds, so it may come up to a second after the
+// time the header says.
+func (s *BulkScheduler) quota() (RateLimitInfo, time.Time) {
+	s.client.rateLimits.mu.Lock()
+	defer s.client.rateLimits.mu.Unlock()
+	info := s.client.rateLimits.last
+	if info.Reset.IsZero() {
+		return info, time.Time{}
+	}
+	return info, info.Reset.Add(time.Second)
+}
+
+// finish records a finished task and reports progress.
+func (s *BulkScheduler) finish(items int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done += items
+	if err != nil {
+		s.failed += items
+	}
+	if s.opts.OnProgress == nil {
+		return
+	}
+
+	p := BulkProgress{Done: s.done, Failed: s.failed, Elapsed: s.client.clock.Now().Sub(s.started)}
+	if s.total > 0 {
+		p.Total = max(s.total, s.done)
+	}
+	if p.Elapsed > 0 {
+		p.Rate = float64(p.Done) / p.Elapsed.Seconds()
+	}
+	if p.Total > 0 && p.Rate > 0 {
+		p.ETA = time.Duration(float64(p.Total-p.Done) / p.Rate * float64(time.Second))
+	}
+	s.opts.OnProgress(p)
+}
@@ -0,0 +1,169 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrConfirmationRequired = errors.New("jwt-revoke: RevokeAll requires Confirm to be set")
+
+type ScopedRevocationResult struct {
+	RevokedCount int `json:"revoked_count"`
+}
+
+type subjectRevokeRequest struct {
+	Subject string     `json:"subject"`
+	Reason  ReasonCode `json:"reason"`
+}
+
+// RevokeBySubject revokes every outstanding token issued for the given sub claim.
+func (s *RevocationsService) RevokeBySubject(ctx context.Context, sub string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("sub", sub); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-subject", subjectRevokeRequest{
+		Subject: sub,
+		Reason:  reason,
+	}, opts...)
+}
+
+type sessionRevokeRequest struct {
+	SessionID string     `json:"sessionId"`
+	Reason    ReasonCode `json:"reason"`
+}
+
+// RevokeSession revokes a login session: every outstanding token carrying
+// the given sid claim, including ones issued later in the session. Use
+// IsSessionRevoked, or Middleware with a Cache, to check it.
+func (s *RevocationsService) RevokeSession(ctx context.Context, sid string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("sid", sid); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-session", sessionRevokeRequest{
+		SessionID: sid,
+		Reason:    reason,
+	}, opts...)
+}
+
+type familyRevokeRequest struct {
+	FamilyID string     `json:"familyId"`
+	Reason   ReasonCode `json:"reason"`
+}
+
+// RevokeFamily revokes every token in a refresh-token family. Call it when a
+// rotated-out refresh token is presented again, which signals the family
+// has been stolen.
+func (s *RevocationsService) RevokeFamily(ctx context.Context, familyID string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("familyId", familyID); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-family", familyRevokeRequest{
+		FamilyID: familyID,
+		Reason:   reason,
+	}, opts...)
+}
+
+type keyRevokeRequest struct {
+	KeyID  string     `json:"keyId"`
+	Reason ReasonCode `json:"reason"`
+}
+
+// RevokeByKeyID revokes every outstanding token signed with the key whose
+// kid is given. Call it when a signing key is compromised, after removing
+// the key from the issuer's key set.
+func (s *RevocationsService) RevokeByKeyID(ctx context.Context, kid string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("keyId", kid); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-key", keyRevokeRequest{
+		KeyID:  kid,
+		Reason: reason,
+	}, opts...)
+}
+
+type issuerRevokeRequest struct {
+	Issuer string     `json:"issuer"`
+	Reason ReasonCode `json:"reason"`
+}
+
+type audienceRevokeRequest struct {
+	Audience string     `json:"audience"`
+	Reason   ReasonCode `json:"reason"`
+}
+
+// RevokeByIssuer revokes every outstanding token carrying the given iss claim.
+func (s *RevocationsService) RevokeByIssuer(ctx context.Context, issuer string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("issuer", issuer); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-issuer", issuerRevokeRequest{
+		Issuer: issuer,
+		Reason: reason,
+	}, opts...)
+}
+
+// RevokeByAudience revokes every outstanding token issued for the given aud claim.
+func (s *RevocationsService) RevokeByAudience(ctx context.Context, audience string, reason ReasonCode, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if err := validateClaim("audience", audience); err != nil {
+		return nil, err
+	}
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-audience", audienceRevokeRequest{
+		Audience: audience,
+		Reason:   reason,
+	}, opts...)
+}
+
+type RevokeAllOptions struct {
+	// Confirm must be true; it guards against invalidating every token by accident.
+	Confirm bool       `json:"confirm"`
+	Reason  ReasonCode `json:"reason"`
+}
+
+// RevokeAll invalidates every outstanding token for the account.
+func (s *RevocationsService) RevokeAll(ctx context.Context, params RevokeAllOptions, opts ...CallOption) (*ScopedRevocationResult, error) {
+	if !params.Confirm {
+		return nil, ErrConfirmationRequired
+	}
+	if err := validateReason(params.Reason); err != nil {
+		return nil, err
+	}
+	return s.client.revokeScope(ctx, "/api/revocations/revoke-all", params, opts...)
+}
+
+func (c *Client) revokeScope(ctx context.Context, path string, payload interface{}, opts ...CallOption) (*ScopedRevocationResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s%s", c.baseURL, path), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[ScopedRevocationResult](ctx, c, req, "", opts...)
+}
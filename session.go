@@ -0,0 +1,45 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// RevokedSession is the revocation of a login session, created by
+// RevokeSession.
+type RevokedSession struct {
+	SessionID string     `json:"session_id"`
+	Reason    ReasonCode `json:"reason"`
+	RevokedAt Timestamp  `json:"revoked_at"`
+}
+
+// SessionChecker is satisfied by *Cache. Middleware checks the sid claim of
+// bearer tokens when its checker also implements it.
+type SessionChecker interface {
+	IsSessionRevoked(ctx context.Context, sid string) (bool, error)
+}
+
+func (s *RevocationsService) GetSession(ctx context.Context, sid string, opts ...CallOption) (*RevokedSession, error) {
+	if err := validateClaim("sid", sid); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/revocations/sessions/%s", s.client.baseURL, url.PathEscape(sid)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[RevokedSession](ctx, s.client, req, "session", opts...)
+}
+
+// IsSessionRevoked asks the API whether the session sid has been revoked.
+func (s *RevocationsService) IsSessionRevoked(ctx context.Context, sid string, opts ...CallOption) (bool, error) {
+	_, err := s.GetSession(ctx, sid, opts...)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
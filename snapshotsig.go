@@ -0,0 +1,81 @@
+package jwtrevokeapi
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	snapshotSignatureHeader = "X-JWTRevoke-Content-Signature"
+	snapshotTimestampHeader = "X-JWTRevoke-Content-Timestamp"
+	snapshotSignaturePrefix = "jwtrevoke-snapshot-v1\n"
+)
+
+// maxSnapshotAge bounds how old a signed list or delta batch may be, so a
+// proxy cannot replay an earlier, validly signed response.
+const maxSnapshotAge = 5 * time.Minute
+
+var ErrInvalidSnapshotSignature = errors.New("jwt-revoke: snapshot signature verification failed")
+
+// WithSnapshotPublicKey makes the client verify the Ed25519 signature the API
+// attaches to full revocation lists and delta batches. The signature covers
+// the request path and query, including since and cursor, a server timestamp
+// and the body. Unsigned or tampered payloads, responses signed for another
+// request and ones signed more than five minutes ago are rejected with
+// ErrInvalidSnapshotSignature, so a compromised CDN or proxy cannot silently
+// drop revocations from a mirror. Pass several keys to accept signatures
+// during a signing key rotation.
+func WithSnapshotPublicKey(keys ...ed25519.PublicKey) ClientOption {
+	return func(c *Client) {
+		c.snapshotKeys = append(c.snapshotKeys, keys...)
+	}
+}
+
+// SnapshotSignatureMessage returns the bytes signed for a list or delta
+// response: requestURI is the path and query of the request, and timestamp
+// is sent in the X-JWTRevoke-Content-Timestamp header as Unix seconds.
+func SnapshotSignatureMessage(requestURI string, timestamp time.Time, body []byte) []byte {
+	msg := []byte(snapshotSignaturePrefix)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(timestamp.Unix()))
+	msg = binary.BigEndian.AppendUint32(msg, uint32(len(requestURI)))
+	msg = append(msg, requestURI...)
+	return append(msg, body...)
+}
+
+// readSnapshotBody returns the response body, verified against the
+// configured snapshot keys when there are any.
+func (c *Client) readSnapshotBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.snapshotKeys) == 0 {
+		return body, nil
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(resp.Header.Get(snapshotSignatureHeader))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, ErrInvalidSnapshotSignature
+	}
+	seconds, err := strconv.ParseInt(resp.Header.Get(snapshotTimestampHeader), 10, 64)
+	if err != nil || resp.Request == nil {
+		return nil, ErrInvalidSnapshotSignature
+	}
+	signedAt := time.Unix(seconds, 0)
+	if age := c.clock.Now().Sub(signedAt); age > maxSnapshotAge || age < -maxSnapshotAge {
+		return nil, ErrInvalidSnapshotSignature
+	}
+	msg := SnapshotSignatureMessage(resp.Request.URL.RequestURI(), signedAt, body)
+	for _, key := range c.snapshotKeys {
+		if ed25519.Verify(key, msg, sig) {
+			return body, nil
+		}
+	}
+	return nil, ErrInvalidSnapshotSignature
+}
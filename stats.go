@@ -0,0 +1,25 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type RevocationStats struct {
+	Total       int `json:"total"`
+	Active      int `json:"active"`
+	Pending     int `json:"pending"`
+	Expired     int `json:"expired"`
+	Last24Hours int `json:"last_24h"`
+	Last7Days   int `json:"last_7d"`
+}
+
+func (s *RevocationsService) Stats(ctx context.Context, opts ...CallOption) (*RevocationStats, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/revocations/stats", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[RevocationStats](ctx, s.client, req, "stats", opts...)
+}
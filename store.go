@@ -0,0 +1,105 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store is the key/value backend used by the cache and mirror subsystems.
+// Implement it to persist revocation state in Memcached, DynamoDB, or any
+// other store; boltstore and redisstore provide ready-made implementations.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value for key and whether it was found. Expired
+	// entries are reported as not found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key. A ttl of zero means the entry never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// Scan calls fn for every unexpired key with the given prefix. Returning
+	// an error from fn stops the scan and is returned from Scan.
+	Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error
+}
+
+// Locker is implemented by stores shared between processes. Mirrors sharing
+// a Locker elect one leader to sync from the API; the others reload from the
+// store whenever the leader has written a newer sync.
+type Locker interface {
+	// TryLock acquires key for ttl, or extends it if already held by this
+	// instance, and reports whether the caller holds the lock.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+// MemoryStore is an in-process Store, useful in tests and as a reference
+// implementation.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entries[key]
+	if !ok || e.expired(time.Now()) {
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.value...), true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	e := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	s.mu.Lock()
+	s.entries[key] = e
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Scan(ctx context.Context, prefix string, fn func(key string, value []byte) error) error {
+	now := time.Now()
+	s.mu.RLock()
+	matched := make(map[string][]byte)
+	for k, e := range s.entries {
+		if strings.HasPrefix(k, prefix) && !e.expired(now) {
+			matched[k] = append([]byte(nil), e.value...)
+		}
+	}
+	s.mu.RUnlock()
+
+	for k, v := range matched {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Stream calls fn for every revocation matching params, following
+// pagination cursors. Pages are decoded incrementally, so memory use stays
+// flat however long the list is. Returning an error from fn stops the stream
+// and is returned as is.
+func (s *RevocationsService) Stream(ctx context.Context, params ListOptions, fn func(RevokedToken) error, opts ...CallOption) error {
+	for {
+		next, err := s.client.streamPage(ctx, params, fn, opts...)
+		if err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		params.Cursor = next
+	}
+}
+
+// streamBuffer is how many revocations ListStream reads ahead of its
+// consumer.
+const streamBuffer = 1000
+
+// ListStream is Stream over a channel: revocations are delivered as they
+// are downloaded, reading ahead of the consumer by a bounded buffer, so
+// processing overlaps the download. The token channel is closed when the
+// list ends, fails, or ctx is cancelled; the error channel then yields the
+// error, if any, and is closed. Cancel ctx to stop early.
+func (s *RevocationsService) ListStream(ctx context.Context, params ListOptions, opts ...CallOption) (<-chan RevokedToken, <-chan error) {
+	tokens := make(chan RevokedToken, streamBuffer)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(tokens)
+		err := s.Stream(ctx, params, func(t RevokedToken) error {
+			select {
+			case tokens <- t:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, opts...)
+		if err != nil {
+			errc <- err
+		}
+		close(errc)
+	}()
+	return tokens, errc
+}
+
+// StreamExpiring calls fn for every revocation whose expiry date falls
+// within the next within, soonest first, e.g. to schedule pruning in a store
+// that mirrors the list. Subscribers see each one as an EventExpired once it
+// passes.
+func (s *RevocationsService) StreamExpiring(ctx context.Context, within time.Duration, fn func(RevokedToken) error, opts ...CallOption) error {
+	now := s.client.clock.Now()
+	return s.Stream(ctx, ListOptions{
+		ExpiresAfter:  now,
+		ExpiresBefore: now.Add(within),
+		SortBy:        SortByExpiryDate,
+		SortOrder:     SortAscending,
+	}, fn, opts...)
+}
+
+func (c *Client) streamPage(ctx context.Context, params ListOptions, fn func(RevokedToken) error, opts ...CallOption) (string, error) {
+	ctx = ContextWithBulkPriority(ctx)
+	endpoint := fmt.Sprintf("%s/api/revocations/list", c.baseURL)
+	if query := params.values().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	c.acceptBulk(req)
+
+	// A listing that spans several requests must see one state of the list,
+	// not pages cached at different times.
+	resp, err := c.doRequest(ctx, req, append(opts, WithCallNoCache())...)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	// A signature covers the whole body, so it has to be read before any
+	// token is trusted; decoding still avoids materializing the page.
+	var body io.Reader = resp.Body
+	if len(c.snapshotKeys) > 0 {
+		verified, err := c.readSnapshotBody(resp)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewReader(verified)
+	}
+
+	if isMsgpack(resp.Header.Get("Content-Type")) {
+		return decodeMsgpackPageStream(c.newMsgpackDecoder(body), c.strictDecoding, fn)
+	}
+	return decodePageStream(c.newDecoder(body), c.strictDecoding, c.responseCodec(), fn)
+}
+
+// decodePageStream walks a {"data": [...], "next_cursor": "..."} object token
+// by token, handing each revocation to fn as soon as it is decoded. strict
+// rejects unknown top-level keys like DisallowUnknownFields does. With a
+// codec, dec only splits the page and the codec decodes each revocation.
+func decodePageStream(dec *json.Decoder, strict bool, codec Codec, fn func(RevokedToken) error) (string, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+
+	var next string
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case "data":
+			tok, err := dec.Token()
+			if err != nil {
+				return "", err
+			}
+			if tok == nil {
+				continue
+			}
+			if d, ok := tok.(json.Delim); !ok || d != '[' {
+				return "", fmt.Errorf("jwt-revoke: unexpected token %v in list response, want [", tok)
+			}
+			for dec.More() {
+				var t RevokedToken
+				if err := decodeItem(dec, codec, &t); err != nil {
+					return "", err
+				}
+				if err := fn(t); err != nil {
+					return "", err
+				}
+			}
+			if err := expectDelim(dec, ']'); err != nil {
+				return "", err
+			}
+		case "next_cursor":
+			if err := dec.Decode(&next); err != nil {
+				return "", err
+			}
+		default:
+			if strict {
+				return "", fmt.Errorf("json: unknown field %q", key)
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return "", err
+			}
+		}
+	}
+	return next, expectDelim(dec, '}')
+}
+
+func decodeItem(dec *json.Decoder, codec Codec, v any) error {
+	if codec == nil {
+		return dec.Decode(v)
+	}
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	return codec.Unmarshal(raw, v)
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("jwt-revoke: unexpected token %v in list response, want %v", tok, want)
+	}
+	return nil
+}
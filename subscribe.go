@@ -0,0 +1,142 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// longPollWait is how long the server may hold a changes request open.
+	longPollWait = 30 * time.Second
+	// subscribeErrorBackoff is the pause after a failed poll.
+	subscribeErrorBackoff = 5 * time.Second
+	// subscribePollInterval is the pause between polls that found nothing,
+	// for accounts without long polling.
+	subscribePollInterval = 10 * time.Second
+)
+
+// Subscription delivers revocation events as they happen. The API has no
+// streaming endpoint, so events are fetched by long polling the changes
+// endpoint, which works through proxies that break SSE and WebSockets. On
+// plans whose Capabilities do not offer long polling, the endpoint is polled
+// on an interval instead.
+type Subscription struct {
+	events chan RevocationEvent
+	done   chan struct{}
+	err    error
+}
+
+// Subscribe delivers every event after since, in order, until ctx is done,
+// the client is closed, or the API rejects the subscription, e.g. because
+// the key was revoked. Transient failures are retried. Events must be
+// received promptly: the subscription does not poll again until the last
+// batch is consumed.
+func (s *RevocationsService) Subscribe(ctx context.Context, since time.Time, opts ...CallOption) *Subscription {
+	sub := &Subscription{events: make(chan RevocationEvent), done: make(chan struct{})}
+	s.client.startPolling(ctx, sub.done, func(ctx context.Context) {
+		defer close(sub.events)
+		sub.err = pollChanges(ctx, s.client, since, opts, sub.events,
+			func(ctx context.Context, since time.Time, wait time.Duration, opts []CallOption) ([]RevocationEvent, Timestamp, error) {
+				set, err := s.changes(ctx, since, wait, opts...)
+				if err != nil {
+					return nil, Timestamp{}, err
+				}
+				s.client.events.publish(set.Events)
+				if !set.ServerTime.IsZero() {
+					s.client.events.covered(since, set.ServerTime.Time)
+				}
+				return set.Events, set.ServerTime, nil
+			})
+	})
+	return sub
+}
+
+// Events is closed when the subscription ends; Err then reports why.
+func (sub *Subscription) Events() <-chan RevocationEvent {
+	return sub.events
+}
+
+// Err returns nil while events are flowing, ctx's error after a
+// cancellation, ErrClientClosed after Client.Close, or the error that ended
+// the subscription. Only call it after Events has been closed.
+func (sub *Subscription) Err() error {
+	return sub.err
+}
+
+// Active reports whether the subscription is still running, and otherwise
+// the error that ended it.
+func (sub *Subscription) Active() (bool, error) {
+	select {
+	case <-sub.done:
+		return false, sub.err
+	default:
+		return true, nil
+	}
+}
+
+// startPolling runs poll in the background until ctx is done or the client
+// is closed, which cancels poll's context with ErrClientClosed. done must be
+// closed by startPolling only, once poll has returned; Close waits for it.
+func (c *Client) startPolling(ctx context.Context, done chan struct{}, poll func(ctx context.Context)) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stopOnClose := context.AfterFunc(c.lifecycle.ctx, func() { cancel(ErrClientClosed) })
+	unregister := c.lifecycle.register(false, func(ctx context.Context) error {
+		return waitDone(ctx, done)
+	})
+	go func() {
+		defer close(done)
+		defer unregister()
+		defer stopOnClose()
+		defer cancel(nil)
+		poll(ctx)
+	}()
+}
+
+// pollChanges long polls with fetch, or polls on an interval without long
+// polling, and sends what it returns to events in order, until ctx is done
+// or the API rejects the poll. Transient failures are retried. It returns
+// why it stopped.
+func pollChanges[T any](ctx context.Context, c *Client, since time.Time, opts []CallOption, events chan<- T, fetch func(ctx context.Context, since time.Time, wait time.Duration, opts []CallOption) ([]T, Timestamp, error)) error {
+	opts = append([]CallOption{WithCallTimeout(longPollWait + c.requestTimeout), WithCallNoCache(), WithBulkPriority()}, opts...)
+	for {
+		wait, interval := longPollWait, time.Duration(0)
+		if caps := c.capabilities(ctx, opts...); caps != nil && !caps.SupportsTransport(TransportLongPoll) {
+			wait, interval = 0, subscribePollInterval
+			if caps.PollIntervalSeconds > 0 {
+				interval = time.Duration(caps.PollIntervalSeconds) * time.Second
+			}
+		}
+		batch, serverTime, err := fetch(ctx, since, wait, opts)
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if err != nil {
+			var clientErr *ClientError
+			if errors.As(err, &clientErr) && clientErr.StatusCode < 500 && clientErr.StatusCode != http.StatusTooManyRequests {
+				return err
+			}
+			c.log(ctx, slog.LevelWarn, "jwtrevoke: polling for changes failed, retrying", "error", err)
+			if sleepContext(ctx, c.clock, subscribeErrorBackoff) != nil {
+				return context.Cause(ctx)
+			}
+			continue
+		}
+
+		for _, ev := range batch {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		}
+		if !serverTime.IsZero() {
+			since = serverTime.Time
+		}
+		if len(batch) == 0 && interval > 0 && sleepContext(ctx, c.clock, interval) != nil {
+			return context.Cause(ctx)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a time.Time that decodes every timestamp format the API has
+// been seen to return: RFC 3339 with or without fractional seconds, the same
+// without a timezone (taken as UTC), and Unix epoch seconds as a number or
+// string. It always encodes as RFC 3339.
+type Timestamp struct {
+	time.Time
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseTimestamp(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// ParseTimestamp parses s in any of the formats Timestamp accepts. An empty
+// string is the zero time.
+func ParseTimestamp(s string) (Timestamp, error) {
+	if s == "" {
+		return Timestamp{}, nil
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return Timestamp{time.Unix(int64(whole), int64(frac*1e9)).UTC()}, nil
+	}
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return Timestamp{parsed}, nil
+		}
+	}
+	return Timestamp{}, fmt.Errorf("jwt-revoke: unrecognized timestamp %q", s)
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return t.Time.MarshalJSON()
+}
@@ -0,0 +1,48 @@
+package jwtrevokeapi
+
+import "time"
+
+const defaultExpirySkew = time.Minute
+
+// WithExpirySkew sets how long past a token's exp claim a revocation derived
+// from it is kept, covering verifiers whose clocks run behind. Defaults to
+// one minute.
+func WithExpirySkew(skew time.Duration) ClientOption {
+	return func(c *Client) {
+		if skew >= 0 {
+			c.expirySkew = skew
+		}
+	}
+}
+
+// withTokenClaims fills JwtID and, when ExpiryDate is nil, ExpiryDate from
+// r.Token's claims, so the entry lapses once the token is no longer accepted,
+// and KeyID from its kid header.
+func (r RevokeRequest) withTokenClaims(skew time.Duration) (RevokeRequest, *ValidationError) {
+	if r.Token == "" {
+		return r, nil
+	}
+	claims, err := parseClaims(r.Token)
+	if err != nil {
+		return r, &ValidationError{Field: "token", Message: err.Error()}
+	}
+	switch {
+	case claims.JwtID == "" && r.JwtID == "":
+		return r, &ValidationError{Field: "token", Message: "has no jti claim"}
+	case r.JwtID == "":
+		r.JwtID = claims.JwtID
+	case claims.JwtID != "" && claims.JwtID != r.JwtID:
+		return r, &ValidationError{Field: "jwtId", Message: "does not match the token's jti claim"}
+	}
+	switch kid := tokenKeyID(r.Token); {
+	case r.KeyID == "":
+		r.KeyID = kid
+	case kid != "" && kid != r.KeyID:
+		return r, &ValidationError{Field: "keyId", Message: "does not match the token's kid header"}
+	}
+	if r.ExpiryDate == nil && claims.ExpiresAt != nil {
+		expiry := claims.ExpiresAt.Time.Add(skew)
+		r.ExpiryDate = &expiry
+	}
+	return r, nil
+}
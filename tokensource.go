@@ -0,0 +1,62 @@
+package jwtrevokeapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// TokenSource finds the JWT a request carries, for
+// MiddlewareOptions.TokenSources.
+type TokenSource func(r *http.Request) (string, bool)
+
+// TokenFromAuthorization reads the bearer token in the Authorization header.
+func TokenFromAuthorization() TokenSource {
+	return bearerToken
+}
+
+// TokenFromHeader reads the token from the named header, for gateways that
+// forward it in their own, e.g. X-Forwarded-Access-Token. A Bearer prefix is
+// removed.
+func TokenFromHeader(name string) TokenSource {
+	return func(r *http.Request) (string, bool) {
+		token := strings.TrimSpace(r.Header.Get(name))
+		if scheme, rest, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(rest)
+		}
+		return token, token != ""
+	}
+}
+
+func TokenFromCookie(name string) TokenSource {
+	return func(r *http.Request) (string, bool) {
+		cookie, err := r.Cookie(name)
+		if err != nil {
+			return "", false
+		}
+		return cookie.Value, cookie.Value != ""
+	}
+}
+
+// TokenFromQuery reads the token from a query parameter, e.g. for WebSocket
+// upgrades that cannot set headers. Query strings tend to be logged, so
+// prefer a header where possible.
+func TokenFromQuery(param string) TokenSource {
+	return func(r *http.Request) (string, bool) {
+		token := r.URL.Query().Get(param)
+		return token, token != ""
+	}
+}
+
+// requestToken returns the first token sources find in r, looking in the
+// Authorization header when there are none.
+func requestToken(r *http.Request, sources []TokenSource) (string, bool) {
+	if len(sources) == 0 {
+		return bearerToken(r)
+	}
+	for _, source := range sources {
+		if token, ok := source(r); ok {
+			return token, true
+		}
+	}
+	return "", false
+}
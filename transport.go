@@ -0,0 +1,206 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+var ErrCertificatePinMismatch = errors.New("jwt-revoke: server certificate does not match any pinned public key")
+
+// WithTLSConfig sets the TLS configuration used for connections to the API,
+// e.g. custom root CAs for a self-hosted instance.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = cfg.Clone()
+	}
+}
+
+// WithClientCertificate presents cert for mutual TLS authentication.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return func(c *Client) {
+		if c.tlsConfig == nil {
+			c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		c.tlsConfig.Certificates = append(c.tlsConfig.Certificates, cert)
+	}
+}
+
+// WithPinnedCertificates pins the API's certificate chain to the given
+// base64-encoded SHA-256 hashes of SubjectPublicKeyInfo, optionally prefixed
+// with "sha256/". Connections fail closed unless some certificate in the
+// verified chain matches one of the pins.
+func WithPinnedCertificates(spkiHashes []string) ClientOption {
+	return func(c *Client) {
+		c.pinnedSPKI = make(map[string]bool, len(spkiHashes))
+		for _, h := range spkiHashes {
+			c.pinnedSPKI[strings.TrimPrefix(h, "sha256/")] = true
+		}
+	}
+}
+
+// WithHTTPClient uses hc for all requests. If its Transport is an
+// *http.Transport, the client works on a clone so TLS, pinning, and proxy
+// options still apply; other RoundTrippers are used as-is.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		copied := *hc
+		c.client = &copied
+	}
+}
+
+// WithTransportMiddleware wraps the SDK's HTTP transport, for example with
+// instrumentation, extra authentication, or fault injection. The first
+// middleware is outermost and sees each request first. Every retry attempt
+// passes through the chain.
+func WithTransportMiddleware(middleware ...func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transportMiddleware = append(c.transportMiddleware, middleware...)
+	}
+}
+
+// WithProxy routes all requests through proxyURL. Passing nil disables
+// proxying, including proxies configured via HTTPS_PROXY and friends.
+// Without this option the standard proxy environment variables are honored.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *Client) {
+		c.proxyConfigured = true
+		c.proxyURL = proxyURL
+	}
+}
+
+// WithDialContext replaces the dialer used to open connections to the API.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(c *Client) {
+		c.dialContext = dial
+	}
+}
+
+// WithUnixSocket sends all requests over the unix domain socket at path, e.g.
+// to a local revocation sidecar. The host in the base URL is ignored for
+// dialing, so pair it with WithBaseURL("http://localhost").
+func WithUnixSocket(path string) ClientOption {
+	return WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	})
+}
+
+// TransportTuning controls connection pooling. Zero fields keep the
+// http.DefaultTransport values, whose MaxIdleConnsPerHost of 2 causes
+// connection churn at high request volumes.
+type TransportTuning struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	// ForceHTTP2 negotiates HTTP/2 even when a custom transport, dialer or
+	// TLS config is set.
+	ForceHTTP2 bool
+}
+
+func WithTransportTuning(tuning TransportTuning) ClientOption {
+	return func(c *Client) {
+		c.tuning = &tuning
+	}
+}
+
+func (c *Client) customizesTransport() bool {
+	return c.tlsConfig != nil || len(c.pinnedSPKI) > 0 || c.proxyConfigured || c.dialContext != nil || c.tuning != nil
+}
+
+func (c *Client) verifyPinnedConnection(cs tls.ConnectionState) error {
+	for _, chain := range cs.VerifiedChains {
+		for _, cert := range chain {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			if c.pinnedSPKI[base64.StdEncoding.EncodeToString(sum[:])] {
+				return nil
+			}
+		}
+	}
+	return ErrCertificatePinMismatch
+}
+
+// configureTransport applies the client's transport options to a clone of rt,
+// falling back to http.DefaultTransport when rt is nil.
+func (c *Client) configureTransport(rt http.RoundTripper) http.RoundTripper {
+	var t *http.Transport
+	switch base := rt.(type) {
+	case nil:
+		if !c.customizesTransport() {
+			return nil
+		}
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = base.Clone()
+	default:
+		return rt
+	}
+
+	switch {
+	case c.proxyConfigured && c.proxyURL != nil:
+		t.Proxy = http.ProxyURL(c.proxyURL)
+	case c.proxyConfigured:
+		t.Proxy = nil
+	case t.Proxy == nil:
+		t.Proxy = http.ProxyFromEnvironment
+	}
+
+	if c.dialContext != nil {
+		t.DialContext = c.dialContext
+	}
+
+	if c.tuning != nil {
+		if c.tuning.MaxIdleConns > 0 {
+			t.MaxIdleConns = c.tuning.MaxIdleConns
+		}
+		if c.tuning.MaxIdleConnsPerHost > 0 {
+			t.MaxIdleConnsPerHost = c.tuning.MaxIdleConnsPerHost
+		}
+		if c.tuning.MaxConnsPerHost > 0 {
+			t.MaxConnsPerHost = c.tuning.MaxConnsPerHost
+		}
+		if c.tuning.IdleConnTimeout > 0 {
+			t.IdleConnTimeout = c.tuning.IdleConnTimeout
+		}
+	}
+
+	if c.tlsConfig != nil {
+		t.TLSClientConfig = c.tlsConfig.Clone()
+	}
+	if len(c.pinnedSPKI) > 0 {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		next := t.TLSClientConfig.VerifyConnection
+		t.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if err := c.verifyPinnedConnection(cs); err != nil {
+				return err
+			}
+			if next != nil {
+				return next(cs)
+			}
+			return nil
+		}
+	}
+	if c.tuning != nil && c.tuning.ForceHTTP2 {
+		// A transport passed with WithHTTPClient may have been built
+		// without HTTP/2 support; configuring it explicitly offers h2 in
+		// the TLS handshake whatever the dialer and TLS config.
+		t.ForceAttemptHTTP2 = true
+		if err := http2.ConfigureTransport(t); err != nil {
+			c.log(context.Background(), slog.LevelWarn, "jwtrevoke: enabling HTTP/2 failed", "error", err)
+		}
+	}
+	return t
+}
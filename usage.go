@@ -0,0 +1,42 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type Usage struct {
+	Plan                 string    `json:"plan"`
+	PeriodStart          Timestamp `json:"period_start"`
+	PeriodEnd            Timestamp `json:"period_end"`
+	RequestsUsed         int64     `json:"requests_used"`
+	RequestLimit         int64     `json:"request_limit"`
+	RevocationEntries    int64     `json:"revocation_entries"`
+	RevocationEntryLimit int64     `json:"revocation_entry_limit"`
+}
+
+// RequestQuotaUsed returns the fraction of the period's request quota consumed, or 0 for unlimited plans.
+func (u *Usage) RequestQuotaUsed() float64 {
+	if u.RequestLimit <= 0 {
+		return 0
+	}
+	return float64(u.RequestsUsed) / float64(u.RequestLimit)
+}
+
+// EntryQuotaUsed returns the fraction of the plan's revocation entry limit consumed, or 0 for unlimited plans.
+func (u *Usage) EntryQuotaUsed() float64 {
+	if u.RevocationEntryLimit <= 0 {
+		return 0
+	}
+	return float64(u.RevocationEntries) / float64(u.RevocationEntryLimit)
+}
+
+func (c *Client) Usage(ctx context.Context, opts ...CallOption) (*Usage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/usage", c.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[Usage](ctx, c, req, "usage", opts...)
+}
@@ -0,0 +1,52 @@
+package jwtrevokeapi
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+const modulePath = "github.com/jwtrevoke/go-sdk"
+
+// defaultUserAgent identifies the SDK and Go versions, e.g.
+// "jwtrevoke-go/v1.4.0 go/1.22.3".
+var defaultUserAgent = "jwtrevoke-go/" + moduleVersion() + " go/" + strings.TrimPrefix(runtime.Version(), "go")
+
+// moduleVersion reads the SDK's version from the build information of the
+// program importing it. Builds from a local checkout report "devel".
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	version := ""
+	if info.Main.Path == modulePath {
+		version = info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			version = dep.Version
+			if dep.Replace != nil {
+				version = dep.Replace.Version
+			}
+		}
+	}
+	// Local checkouts and directory replacements carry no real version.
+	if version == "" || version == "(devel)" {
+		return "devel"
+	}
+	return version
+}
+
+// WithAppInfo appends name/version to the User-Agent header so support can
+// tell which application sent a request. It can be given more than once,
+// for example by a library and by the application using it.
+func WithAppInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		product := name
+		if version != "" {
+			product += "/" + version
+		}
+		c.userAgent += " " + product
+	}
+}
@@ -0,0 +1,118 @@
+package jwtrevokeapi
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits enforced by the API, checked locally so bad input fails before a
+// request is sent.
+const (
+	MaxJwtIDLength  = 256
+	MaxReasonLength = 1024
+)
+
+// ValidationError reports input rejected by the client before any request
+// was made. Field is the JSON name of the offending field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("jwt-revoke: invalid %s: %s", e.Field, e.Message)
+}
+
+// Validate checks r against the API's rules: a well-formed JWT ID, a reason
+// and detail within MaxReasonLength, metadata within its limits, an expiry
+// in the future, and an effective time before the expiry.
+func (r RevokeRequest) Validate() error {
+	if err := r.validate(time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r RevokeRequest) validate(now time.Time) *ValidationError {
+	r, err := r.withTokenClaims(0)
+	if err != nil {
+		return err
+	}
+	if err := validateJwtID("jwtId", r.JwtID); err != nil {
+		return err
+	}
+	if err := validateReason(r.Reason); err != nil {
+		return err
+	}
+	if err := validateReasonDetail(r.ReasonDetail); err != nil {
+		return err
+	}
+	if err := r.Metadata.validate(); err != nil {
+		return err
+	}
+	if r.ExpiryDate != nil && !r.ExpiryDate.After(now) {
+		return &ValidationError{Field: "expiryDate", Message: "must be in the future"}
+	}
+	if r.ExpiryDate != nil && r.EffectiveAt != nil && !r.EffectiveAt.Before(*r.ExpiryDate) {
+		return &ValidationError{Field: "effectiveAt", Message: "must be before expiryDate"}
+	}
+	return nil
+}
+
+// Validate checks the fields set on u with the same rules as RevokeRequest.
+func (u UpdateRequest) Validate() error {
+	if u.Reason != nil {
+		if err := validateReason(*u.Reason); err != nil {
+			return err
+		}
+	}
+	if u.ReasonDetail != nil {
+		if err := validateReasonDetail(*u.ReasonDetail); err != nil {
+			return err
+		}
+	}
+	if u.ExpiryDate != nil && !u.ExpiryDate.After(time.Now()) {
+		return &ValidationError{Field: "expiryDate", Message: "must be in the future"}
+	}
+	return nil
+}
+
+func validateJwtID(field, jwtID string) *ValidationError {
+	switch {
+	case jwtID == "":
+		return &ValidationError{Field: field, Message: "must not be empty"}
+	case len(jwtID) > MaxJwtIDLength:
+		return &ValidationError{Field: field, Message: fmt.Sprintf("must be at most %d bytes", MaxJwtIDLength)}
+	case !utf8.ValidString(jwtID):
+		return &ValidationError{Field: field, Message: "must be valid UTF-8"}
+	}
+	for _, r := range jwtID {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return &ValidationError{Field: field, Message: "must not contain whitespace or control characters"}
+		}
+	}
+	return nil
+}
+
+func validateReason(reason ReasonCode) *ValidationError {
+	if utf8.RuneCountInString(string(reason)) > MaxReasonLength {
+		return &ValidationError{Field: "reason", Message: fmt.Sprintf("must be at most %d characters", MaxReasonLength)}
+	}
+	return nil
+}
+
+func validateReasonDetail(detail string) *ValidationError {
+	if utf8.RuneCountInString(detail) > MaxReasonLength {
+		return &ValidationError{Field: "reasonDetail", Message: fmt.Sprintf("must be at most %d characters", MaxReasonLength)}
+	}
+	return nil
+}
+
+func validateClaim(field, value string) *ValidationError {
+	if value == "" {
+		return &ValidationError{Field: field, Message: "must not be empty"}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package vaultcreds loads the JWT Revoke API key from a HashiCorp Vault KV
+// version 2 secret, so the key never has to live in environment variables
+// or images.
+package vaultcreds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+	jwtrevokeapi "github.com/jwtrevoke/go-sdk"
+)
+
+const (
+	defaultMount           = "secret"
+	defaultField           = "api_key"
+	defaultRefreshInterval = 5 * time.Minute
+)
+
+var _ jwtrevokeapi.CredentialsProvider = (*Provider)(nil)
+
+// Provider re-reads the secret once the refresh interval has passed, or
+// straight away after the API rejects the current key.
+type Provider struct {
+	client  *vault.Client
+	path    string
+	mount   string
+	field   string
+	refresh time.Duration
+	cache   *jwtrevokeapi.CachingCredentials
+}
+
+type Option func(*Provider)
+
+// WithMount sets the KV engine's mount path. Defaults to "secret".
+func WithMount(mount string) Option {
+	return func(p *Provider) {
+		p.mount = mount
+	}
+}
+
+// WithField names the secret's key holding the API key. Defaults to "api_key".
+func WithField(field string) Option {
+	return func(p *Provider) {
+		p.field = field
+	}
+}
+
+// WithRefreshInterval sets how long a fetched key is used before Vault is
+// asked again. Defaults to five minutes.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(p *Provider) {
+		p.refresh = d
+	}
+}
+
+// New reads the API key from the secret at path, relative to the mount.
+func New(client *vault.Client, path string, options ...Option) *Provider {
+	p := &Provider{client: client, path: path, mount: defaultMount, field: defaultField, refresh: defaultRefreshInterval}
+	for _, option := range options {
+		option(p)
+	}
+	p.cache = jwtrevokeapi.NewCachingCredentials(jwtrevokeapi.CredentialsFunc(p.fetch), p.refresh)
+	return p
+}
+
+func (p *Provider) APIKey(ctx context.Context) (string, error) {
+	return p.cache.APIKey(ctx)
+}
+
+// Invalidate makes the next APIKey call read the secret again.
+func (p *Provider) Invalidate() {
+	p.cache.Invalidate()
+}
+
+func (p *Provider) fetch(ctx context.Context) (string, error) {
+	secret, err := p.client.KVv2(p.mount).Get(ctx, p.path)
+	if err != nil {
+		return "", fmt.Errorf("vaultcreds: reading %s/%s: %w", p.mount, p.path, err)
+	}
+	key, ok := secret.Data[p.field].(string)
+	if !ok || key == "" {
+		return "", fmt.Errorf("vaultcreds: %s/%s has no %q field", p.mount, p.path, p.field)
+	}
+	return key, nil
+}
@@ -0,0 +1,167 @@
+package jwtrevokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+	// DeliveryPending deliveries are waiting for their first attempt or a
+	// scheduled retry.
+	DeliveryPending DeliveryStatus = "pending"
+)
+
+// WebhookDelivery is one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	// EventID is shared by every attempt to deliver the same event.
+	EventID   string         `json:"event_id"`
+	EventType EventType      `json:"event_type"`
+	Status    DeliveryStatus `json:"status"`
+	// StatusCode is the endpoint's response code, zero if it could not be
+	// reached; Error then says why.
+	StatusCode  int        `json:"status_code,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Attempt     int        `json:"attempt"`
+	DurationMs  int        `json:"duration_ms"`
+	CreatedAt   Timestamp  `json:"created_at"`
+	NextRetryAt *Timestamp `json:"next_retry_at,omitempty"`
+}
+
+// WebhookDeliveryDetail adds what was sent and received to a delivery.
+type WebhookDeliveryDetail struct {
+	WebhookDelivery
+	RequestHeaders  http.Header `json:"request_headers"`
+	RequestBody     string      `json:"request_body"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+type DeliveryFilter struct {
+	Status DeliveryStatus
+	From   time.Time
+	To     time.Time
+	// Cursor continues a previous listing; use DeliveryPage.NextCursor.
+	Cursor string
+	Limit  int
+}
+
+type DeliveryPage struct {
+	Deliveries []WebhookDelivery `json:"data"`
+	NextCursor string            `json:"next_cursor"`
+}
+
+func (p *DeliveryPage) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+func (f DeliveryFilter) values() url.Values {
+	v := url.Values{}
+	if f.Status != "" {
+		v.Set("status", string(f.Status))
+	}
+	setTime(v, "from", f.From)
+	setTime(v, "to", f.To)
+	if f.Cursor != "" {
+		v.Set("cursor", f.Cursor)
+	}
+	if f.Limit > 0 {
+		v.Set("limit", strconv.Itoa(f.Limit))
+	}
+	return v
+}
+
+// ListDeliveries returns a webhook's delivery attempts, newest first.
+func (s *WebhooksService) ListDeliveries(ctx context.Context, webhookID string, filter DeliveryFilter, opts ...CallOption) (*DeliveryPage, error) {
+	endpoint := fmt.Sprintf("%s/api/webhooks/%s/deliveries", s.client.baseURL, url.PathEscape(webhookID))
+	if query := filter.values().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[DeliveryPage](ctx, s.client, req, "", opts...)
+}
+
+// GetDelivery returns a delivery with the request that was sent and the
+// endpoint's response. With WithPIIRedaction, email addresses in the bodies
+// are redacted.
+func (s *WebhooksService) GetDelivery(ctx context.Context, webhookID, deliveryID string, opts ...CallOption) (*WebhookDeliveryDetail, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/webhooks/%s/deliveries/%s", s.client.baseURL, url.PathEscape(webhookID), url.PathEscape(deliveryID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := call[WebhookDeliveryDetail](ctx, s.client, req, "delivery", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.RequestBody = s.client.redactEmails(delivery.RequestBody)
+	delivery.ResponseBody = s.client.redactEmails(delivery.ResponseBody)
+	return delivery, nil
+}
+
+// Redeliver sends a delivery's event again and returns the new attempt.
+func (s *WebhooksService) Redeliver(ctx context.Context, webhookID, deliveryID string, opts ...CallOption) (*WebhookDelivery, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/webhooks/%s/deliveries/%s/redeliver", s.client.baseURL, url.PathEscape(webhookID), url.PathEscape(deliveryID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[WebhookDelivery](ctx, s.client, req, "delivery", opts...)
+}
+
+// RedeliverFailed redelivers every event whose latest delivery since the
+// given time failed, e.g. once a consumer is back after an outage, and
+// returns the new attempts. Events that later succeeded, or are still
+// pending a retry, are left alone. It stops at the first error, returning
+// the attempts made so far.
+func (s *WebhooksService) RedeliverFailed(ctx context.Context, webhookID string, since time.Time, opts ...CallOption) ([]WebhookDelivery, error) {
+	// Bounding the listing keeps the new attempts out of it.
+	filter := DeliveryFilter{From: since, To: s.client.clock.Now()}
+	latest := map[string]bool{}
+	var failed []WebhookDelivery
+	for {
+		page, err := s.ListDeliveries(ctx, webhookID, filter, opts...)
+		if err != nil {
+			return nil, err
+		}
+		// Deliveries are listed newest first.
+		for _, d := range page.Deliveries {
+			if latest[d.EventID] {
+				continue
+			}
+			latest[d.EventID] = true
+			if d.Status == DeliveryFailed {
+				failed = append(failed, d)
+			}
+		}
+		if !page.HasMore() {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	redelivered := make([]WebhookDelivery, 0, len(failed))
+	for _, d := range failed {
+		attempt, err := s.Redeliver(ctx, webhookID, d.ID, opts...)
+		if err != nil {
+			return redelivered, err
+		}
+		redelivered = append(redelivered, *attempt)
+	}
+	return redelivered, nil
+}
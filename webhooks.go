@@ -0,0 +1,119 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WebhooksService manages the endpoints revocation events are delivered to.
+type WebhooksService struct {
+	client *Client
+}
+
+type Webhook struct {
+	ID          string      `json:"id"`
+	URL         string      `json:"url"`
+	Events      []EventType `json:"events,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Enabled     bool        `json:"enabled"`
+	CreatedAt   Timestamp   `json:"created_at"`
+}
+
+// CreatedWebhook is returned by Create; Secret signs deliveries and is only
+// ever shown once.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+type CreateWebhookRequest struct {
+	URL string `json:"url"`
+	// Events limits deliveries to these event types; empty means all.
+	Events      []EventType `json:"events,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// UpdateWebhookRequest changes only the fields that are set.
+type UpdateWebhookRequest struct {
+	URL         *string     `json:"url,omitempty"`
+	Events      []EventType `json:"events,omitempty"`
+	Description *string     `json:"description,omitempty"`
+	Enabled     *bool       `json:"enabled,omitempty"`
+}
+
+func (s *WebhooksService) Create(ctx context.Context, create CreateWebhookRequest, opts ...CallOption) (*CreatedWebhook, error) {
+	body, err := json.Marshal(create)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/webhooks", s.client.baseURL), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[CreatedWebhook](ctx, s.client, req, "webhook", opts...)
+}
+
+func (s *WebhooksService) List(ctx context.Context, opts ...CallOption) ([]Webhook, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/webhooks", s.client.baseURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := call[[]Webhook](ctx, s.client, req, "data", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *list, nil
+}
+
+func (s *WebhooksService) Get(ctx context.Context, webhookID string, opts ...CallOption) (*Webhook, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/webhooks/%s", s.client.baseURL, url.PathEscape(webhookID)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return call[Webhook](ctx, s.client, req, "webhook", opts...)
+}
+
+func (s *WebhooksService) Update(ctx context.Context, webhookID string, update UpdateWebhookRequest, opts ...CallOption) (*Webhook, error) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", fmt.Sprintf("%s/api/webhooks/%s", s.client.baseURL, url.PathEscape(webhookID)), bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return call[Webhook](ctx, s.client, req, "webhook", opts...)
+}
+
+func (s *WebhooksService) Delete(ctx context.Context, webhookID string, opts ...CallOption) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/api/webhooks/%s", s.client.baseURL, url.PathEscape(webhookID)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.doRequest(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
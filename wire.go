@@ -0,0 +1,152 @@
+package jwtrevokeapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type WireFormat string
+
+const (
+	WireJSON    WireFormat = "json"
+	WireMsgpack WireFormat = "msgpack"
+)
+
+const msgpackContentType = "application/msgpack"
+
+// WithWireFormat selects the encoding used by the bulk endpoints: list pages,
+// and with them Stream, Export, and mirror snapshots, plus batch revocations
+// and imports. Responses are negotiated with an Accept header, so a server
+// that only speaks JSON keeps working.
+func WithWireFormat(format WireFormat) ClientOption {
+	return func(c *Client) {
+		c.wireFormat = format
+	}
+}
+
+// acceptBulk asks for the configured bulk encoding, with JSON as fallback.
+func (c *Client) acceptBulk(req *http.Request) {
+	if c.wireFormat == WireMsgpack {
+		req.Header.Set("Accept", msgpackContentType+", application/json;q=0.9")
+	}
+}
+
+// encodeBulk encodes a bulk request body and returns its content type.
+func (c *Client) encodeBulk(v interface{}) ([]byte, string, error) {
+	if c.wireFormat != WireMsgpack {
+		body, err := c.marshalJSON(v)
+		return body, "application/json", err
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), msgpackContentType, nil
+}
+
+// decodeBulk decodes a bulk response in whichever encoding the server chose.
+func (c *Client) decodeBulk(r io.Reader, contentType string, v interface{}) error {
+	if !isMsgpack(contentType) {
+		return c.decodeJSON(r, v)
+	}
+	return c.newMsgpackDecoder(r).Decode(v)
+}
+
+func (c *Client) newMsgpackDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	dec.DisallowUnknownFields(c.strictDecoding)
+	return dec
+}
+
+func isMsgpack(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == msgpackContentType
+}
+
+// decodeMsgpackPageStream is decodePageStream for msgpack list pages.
+func decodeMsgpackPageStream(dec *msgpack.Decoder, strict bool, fn func(RevokedToken) error) (string, error) {
+	n, err := dec.DecodeMapLen()
+	if err != nil {
+		return "", err
+	}
+
+	var next string
+	for i := 0; i < n; i++ {
+		key, err := dec.DecodeString()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case "data":
+			count, err := dec.DecodeArrayLen()
+			if err != nil {
+				return "", err
+			}
+			for j := 0; j < count; j++ {
+				var t RevokedToken
+				if err := dec.Decode(&t); err != nil {
+					return "", err
+				}
+				if err := fn(t); err != nil {
+					return "", err
+				}
+			}
+		case "next_cursor":
+			if err := dec.Decode(&next); err != nil {
+				return "", err
+			}
+		default:
+			if strict {
+				return "", fmt.Errorf("msgpack: unknown field %q", key)
+			}
+			if err := dec.Skip(); err != nil {
+				return "", err
+			}
+		}
+	}
+	return next, nil
+}
+
+// EncodeMsgpack writes t as a msgpack timestamp.
+func (t Timestamp) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.EncodeTime(t.Time)
+}
+
+// DecodeMsgpack accepts a msgpack timestamp as well as the string and epoch
+// forms Timestamp accepts in JSON.
+func (t *Timestamp) DecodeMsgpack(dec *msgpack.Decoder) error {
+	v, err := dec.DecodeInterface()
+	if err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		t.Time = v
+		return nil
+	case string:
+		parsed, err := ParseTimestamp(v)
+		if err != nil {
+			return err
+		}
+		*t = parsed
+		return nil
+	default:
+		parsed, err := ParseTimestamp(fmt.Sprint(v))
+		if err != nil {
+			return err
+		}
+		*t = parsed
+		return nil
+	}
+}